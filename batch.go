@@ -0,0 +1,55 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// BatchWriter is an io.ByteWriter that collects bytes locally and writes
+// them to a RingBuffer in batches, so that producers writing a byte at a
+// time lock the buffer once per batch instead of once per byte.
+// Like bufio.Writer, it writes a full batch when the next byte is written,
+// and Flush writes the remaining bytes. A BatchWriter is not safe for
+// concurrent use.
+type BatchWriter struct {
+	r   *RingBuffer
+	buf []byte
+}
+
+// BufferedWriter returns a BatchWriter that writes to the buffer in batches
+// of up to batch bytes. A batch of less than 1 is treated as 1.
+func (r *RingBuffer) BufferedWriter(batch int) *BatchWriter {
+	if batch < 1 {
+		batch = 1
+	}
+	return &BatchWriter{r: r, buf: make([]byte, 0, batch)}
+}
+
+// WriteByte adds c to the batch, first writing the batch to the buffer if it
+// is full. It returns the error from Flush if the batch is still full.
+func (b *BatchWriter) WriteByte(c byte) error {
+	if len(b.buf) == cap(b.buf) {
+		if err := b.Flush(); err != nil && len(b.buf) == cap(b.buf) {
+			return err
+		}
+	}
+	b.buf = append(b.buf, c)
+	return nil
+}
+
+// Flush writes the batched bytes to the buffer with a single Write.
+// If they don't all fit, it keeps the bytes that weren't written for the
+// next Flush and returns the error from Write.
+func (b *BatchWriter) Flush() error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	n, err := b.r.Write(b.buf)
+	b.buf = b.buf[:copy(b.buf, b.buf[n:])]
+	return err
+}
+
+// Buffered returns the number of bytes batched but not yet written to the
+// buffer.
+func (b *BatchWriter) Buffered() int {
+	return len(b.buf)
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_BufferedWriter(t *testing.T) {
+	rb := New(8)
+	bw := rb.BufferedWriter(3)
+	var _ io.ByteWriter = bw
+
+	for _, c := range []byte("abcd") {
+		if err := bw.WriteByte(c); err != nil {
+			t.Fatalf("WriteByte failed: %v", err)
+		}
+	}
+	if rb.Length() != 3 || bw.Buffered() != 1 {
+		t.Fatalf("expect 3 bytes written and 1 batched but got %d and %d", rb.Length(), bw.Buffered())
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcd")) {
+		t.Fatalf("expect abcd but got %s", rb.Bytes())
+	}
+
+	// bytes that don't fit are kept for the next Flush
+	for _, c := range []byte("efghijk") {
+		if err := bw.WriteByte(c); err != nil {
+			t.Fatalf("WriteByte failed: %v", err)
+		}
+	}
+	if err := bw.WriteByte('l'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if bw.Buffered() != 3 {
+		t.Fatalf("expect 3 batched bytes but got %d", bw.Buffered())
+	}
+	if _, err := rb.Read(make([]byte, 2)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := bw.Flush(); !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 2)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("efghijk")) {
+		t.Fatalf("expect efghijk but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "encoding/binary"
+
+// ReadUint16BE reads a big-endian uint16.
+// If fewer than 2 bytes are buffered, it returns ErrEmpty without
+// consuming anything, or ErrClosed if the buffer is closed.
+func (r *RingBuffer) ReadUint16BE() (uint16, error) {
+	var p [2]byte
+	if err := r.readFull(p[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(p[:]), nil
+}
+
+// ReadUint32BE reads a big-endian uint32, like ReadUint16BE.
+func (r *RingBuffer) ReadUint32BE() (uint32, error) {
+	var p [4]byte
+	if err := r.readFull(p[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(p[:]), nil
+}
+
+// ReadUint64BE reads a big-endian uint64, like ReadUint16BE.
+func (r *RingBuffer) ReadUint64BE() (uint64, error) {
+	var p [8]byte
+	if err := r.readFull(p[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(p[:]), nil
+}
+
+// ReadUint16LE reads a little-endian uint16, like ReadUint16BE.
+func (r *RingBuffer) ReadUint16LE() (uint16, error) {
+	var p [2]byte
+	if err := r.readFull(p[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(p[:]), nil
+}
+
+// ReadUint32LE reads a little-endian uint32, like ReadUint16BE.
+func (r *RingBuffer) ReadUint32LE() (uint32, error) {
+	var p [4]byte
+	if err := r.readFull(p[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(p[:]), nil
+}
+
+// ReadUint64LE reads a little-endian uint64, like ReadUint16BE.
+func (r *RingBuffer) ReadUint64LE() (uint64, error) {
+	var p [8]byte
+	if err := r.readFull(p[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(p[:]), nil
+}
+
+// WriteUint16BE writes v as a big-endian uint16.
+// Either both bytes are written, or nothing is written and ErrFull or
+// ErrClosed returned.
+func (r *RingBuffer) WriteUint16BE(v uint16) error {
+	var p [2]byte
+	binary.BigEndian.PutUint16(p[:], v)
+	return r.writeFull(p[:])
+}
+
+// WriteUint32BE writes v as a big-endian uint32, like WriteUint16BE.
+func (r *RingBuffer) WriteUint32BE(v uint32) error {
+	var p [4]byte
+	binary.BigEndian.PutUint32(p[:], v)
+	return r.writeFull(p[:])
+}
+
+// WriteUint64BE writes v as a big-endian uint64, like WriteUint16BE.
+func (r *RingBuffer) WriteUint64BE(v uint64) error {
+	var p [8]byte
+	binary.BigEndian.PutUint64(p[:], v)
+	return r.writeFull(p[:])
+}
+
+// WriteUint16LE writes v as a little-endian uint16, like WriteUint16BE.
+func (r *RingBuffer) WriteUint16LE(v uint16) error {
+	var p [2]byte
+	binary.LittleEndian.PutUint16(p[:], v)
+	return r.writeFull(p[:])
+}
+
+// WriteUint32LE writes v as a little-endian uint32, like WriteUint16BE.
+func (r *RingBuffer) WriteUint32LE(v uint32) error {
+	var p [4]byte
+	binary.LittleEndian.PutUint32(p[:], v)
+	return r.writeFull(p[:])
+}
+
+// WriteUint64LE writes v as a little-endian uint64, like WriteUint16BE.
+func (r *RingBuffer) WriteUint64LE(v uint64) error {
+	var p [8]byte
+	binary.LittleEndian.PutUint64(p[:], v)
+	return r.writeFull(p[:])
+}
+
+// readFull reads exactly len(p) bytes under one lock, or reads nothing
+// and returns ErrEmpty or ErrClosed.
+func (r *RingBuffer) readFull(p []byte) error {
+	r.mu.Lock()
+	var err error
+	if r.length() < len(p) {
+		err = r.emptyErr()
+	} else {
+		_, err = r.read(p)
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
+
+// writeFull writes all of p under one lock, or writes nothing and returns
+// ErrFull or ErrClosed.
+func (r *RingBuffer) writeFull(p []byte) error {
+	r.mu.Lock()
+	err := r.writeAll(p)
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRingBuffer_ReadUint(t *testing.T) {
+	rb := New(16)
+
+	// wrap the data around the end of the buffer
+	if _, err := rb.Write(make([]byte, 10)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 10)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte{
+		0x01, 0x02,
+		0x01, 0x02, 0x03, 0x04,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+	}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if v, err := rb.ReadUint16BE(); err != nil || v != 0x0102 {
+		t.Fatalf("expect 0x0102 but got %#x, %v", v, err)
+	}
+	if v, err := rb.ReadUint32LE(); err != nil || v != 0x04030201 {
+		t.Fatalf("expect 0x04030201 but got %#x, %v", v, err)
+	}
+	if _, err := rb.Write([]byte{0xff, 0xfe}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if v, err := rb.ReadUint64BE(); err != nil || v != 0x0102030405060708 {
+		t.Fatalf("expect 0x0102030405060708 but got %#x, %v", v, err)
+	}
+
+	// not enough bytes: nothing is consumed
+	if _, err := rb.ReadUint32BE(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if rb.Length() != 2 {
+		t.Fatalf("expect len 2 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+	if v, err := rb.ReadUint16LE(); err != nil || v != 0xfeff {
+		t.Fatalf("expect 0xfeff but got %#x, %v", v, err)
+	}
+
+	_ = rb.Close()
+	if _, err := rb.ReadUint64LE(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_WriteUint(t *testing.T) {
+	rb := New(16)
+
+	if err := rb.WriteUint16BE(0x0102); err != nil {
+		t.Fatalf("WriteUint16BE failed: %v", err)
+	}
+	if err := rb.WriteUint32LE(0x04030201); err != nil {
+		t.Fatalf("WriteUint32LE failed: %v", err)
+	}
+	if err := rb.WriteUint64BE(0x0102030405060708); err != nil {
+		t.Fatalf("WriteUint64BE failed: %v", err)
+	}
+	if v, err := rb.ReadUint16BE(); err != nil || v != 0x0102 {
+		t.Fatalf("expect 0x0102 but got %#x, %v", v, err)
+	}
+	if v, err := rb.ReadUint32LE(); err != nil || v != 0x04030201 {
+		t.Fatalf("expect 0x04030201 but got %#x, %v", v, err)
+	}
+
+	// 8 bytes buffered, 8 free: a uint64 fits across the wrap
+	if err := rb.WriteUint64LE(0x0807060504030201); err != nil {
+		t.Fatalf("WriteUint64LE failed: %v", err)
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false")
+	}
+
+	// no partial integer writes
+	if err := rb.WriteUint16LE(1); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 3)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := rb.WriteUint32BE(1); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if rb.Length() != 13 {
+		t.Fatalf("expect len 13 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	if _, err := rb.Read(make([]byte, 5)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if v, err := rb.ReadUint64LE(); err != nil || v != 0x0807060504030201 {
+		t.Fatalf("expect 0x0807060504030201 but got %#x, %v", v, err)
+	}
+
+	_ = rb.Close()
+	if err := rb.WriteUint16BE(1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"time"
+)
+
+// Close closes the buffer.
+// Subsequent writes return ErrClosed, and reads return ErrClosed once the
+// remaining data has been read. Goroutines blocked on the buffer are woken.
+// Close always returns nil.
+func (r *RingBuffer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	r.cond.Broadcast()
+	return nil
+}
+
+// emptyErr returns the error for a read that found too little data: the
+// buffer is empty, or holds only part of what the read needs. Only a read
+// from an empty buffer returns io.EOF with WithEOFOnEmpty.
+// It must be called with r.mu held.
+func (r *RingBuffer) emptyErr() error {
+	if r.closed {
+		return ErrClosed
+	}
+	if r.eofOnEmpty && r.length() == 0 {
+		return io.EOF
+	}
+	return ErrEmpty
+}
+
+// BlockingRead reads up to len(p) bytes into p, waiting until at least one
+// byte is available. It returns ErrClosed if the buffer is closed and empty,
+// and ErrTimeout if the read deadline passes first.
+func (r *RingBuffer) BlockingRead(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	err = r.wait(r.readable, &r.readDeadline)
+	if err == nil {
+		n, err = r.read(r.chunk(p, 0))
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
+
+// ReadBetween reads between min and len(p) bytes into p, waiting until at
+// least min bytes are available. If the buffer is closed first, it reads
+// whatever remains, which may be fewer than min bytes, and returns ErrClosed
+// only if nothing remains. It returns ErrTimeout if the read deadline passes
+// first.
+// It returns io.ErrShortBuffer if min is larger than len(p), and
+// ErrExceedsCapacity if min is larger than the buffer could ever hold.
+func (r *RingBuffer) ReadBetween(p []byte, min int) (n int, err error) {
+	if min > len(p) {
+		return 0, io.ErrShortBuffer
+	}
+
+	r.mu.Lock()
+	if min > r.maxCapacity() {
+		r.mu.Unlock()
+		return 0, ErrExceedsCapacity
+	}
+	err = r.wait(func() bool {
+		return r.length() >= min
+	}, &r.readDeadline)
+	if err == nil || (err == ErrClosed && r.length() > 0) {
+		n, err = r.read(r.chunk(p, min))
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
+
+// BlockingWrite writes all of p to the buffer, waiting for free space as
+// needed. It returns early with ErrClosed if the buffer is closed,
+// and ErrTimeout if the write deadline passes. It returns ErrExceedsCapacity
+// immediately if the buffer can't hold any data.
+func (r *RingBuffer) BlockingWrite(p []byte) (n int, err error) {
+	r.mu.Lock()
+	n, err = r.blockingWrite(context.Background(), p)
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
+
+// blockingWrite is BlockingWrite without locking, which also returns
+// ctx.Err() if ctx is done while waiting. It must be called with r.mu held,
+// and releases it while running the OnFull hook.
+func (r *RingBuffer) blockingWrite(ctx context.Context, p []byte) (n int, err error) {
+	if len(p) > 0 && r.maxCapacity() == 0 {
+		return 0, ErrExceedsCapacity
+	}
+	for len(p) > 0 {
+		var m int
+		m, err = r.write(p)
+		n += m
+		p = p[m:]
+		if err == ErrClosed {
+			break
+		}
+		err = nil
+		if len(p) == 0 {
+			break
+		}
+
+		if hook := r.fullHook(); hook != nil {
+			r.mu.Unlock()
+			hook()
+			r.mu.Lock()
+			continue
+		}
+		if err = r.waitContext(ctx, func() bool {
+			return r.writable(len(p))
+		}, &r.writeDeadline); err != nil {
+			break
+		}
+	}
+	return n, err
+}
+
+// readable reports whether there is data to read.
+// It must be called with r.mu held.
+func (r *RingBuffer) readable() bool {
+	return r.isFull || r.w != r.r
+}
+
+// writable reports whether there is space to write, or whether the buffer
+// would grow for a write of n bytes. Asking about the whole write matters
+// when a grow policy allows small growth but refuses large growth.
+// It must be called with r.mu held.
+func (r *RingBuffer) writable(n int) bool {
+	return !r.isFull || r.canGrow(r.length()+n)
+}
+
+// wait blocks until ready returns true. It returns ErrClosed if the buffer
+// is closed, or ErrTimeout if the deadline passes, before then.
+// The deadline is re-read after every wakeup, so it may be changed while
+// waiting. It must be called with r.mu held.
+func (r *RingBuffer) wait(ready func() bool, deadline *time.Time) error {
+	return r.waitContext(context.Background(), ready, deadline)
+}
+
+// waitContext is wait, but also returns ctx.Err() if ctx is done before
+// ready returns true. It must be called with r.mu held.
+func (r *RingBuffer) waitContext(ctx context.Context, ready func() bool, deadline *time.Time) error {
+	if done := ctx.Done(); done != nil && !ready() {
+		// Wake the waiters when ctx is done, as the deadline timers do.
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-done:
+				r.mu.Lock()
+				r.cond.Broadcast()
+				r.mu.Unlock()
+			case <-stop:
+			}
+		}()
+	}
+
+	if !ready() {
+		r.waiting(deadline, 1)
+		defer r.waiting(deadline, -1)
+	}
+
+	spins := r.spins
+	for !ready() {
+		if r.closed {
+			return ErrClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !deadline.IsZero() && !time.Now().Before(*deadline) {
+			return ErrTimeout
+		}
+		if spins > 0 {
+			spins--
+			r.mu.Unlock()
+			runtime.Gosched()
+			r.mu.Lock()
+			continue
+		}
+		r.cond.Wait()
+	}
+	return nil
+}
+
+// WaitForData blocks until at least n bytes are available to read.
+// It returns ErrClosed if the buffer is closed, or ErrTimeout if the read
+// deadline passes, before then. It returns ErrExceedsCapacity immediately
+// if n is larger than the buffer could ever hold.
+// Once WaitForData returns nil, a non-blocking read of n bytes succeeds
+// unless another goroutine reads from the buffer first.
+func (r *RingBuffer) WaitForData(n int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n > r.maxCapacity() {
+		return ErrExceedsCapacity
+	}
+	return r.wait(func() bool {
+		return r.length() >= n
+	}, &r.readDeadline)
+}
+
+// maxCapacity returns the largest size the buffer can grow to.
+// It must be called with r.mu held.
+func (r *RingBuffer) maxCapacity() int {
+	if r.maxSize > r.size {
+		return r.maxSize
+	}
+	return r.size
+}
+
+// WaitForSpace blocks until a write of n bytes would fit in the buffer.
+// It returns ErrClosed if the buffer is closed, or ErrTimeout if the write
+// deadline passes, before then. It returns ErrExceedsCapacity immediately
+// if n is larger than the buffer could ever hold.
+// Once WaitForSpace returns nil, a non-blocking write of n bytes succeeds
+// unless another goroutine writes to the buffer first.
+func (r *RingBuffer) WaitForSpace(n int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n > r.maxCapacity() {
+		return ErrExceedsCapacity
+	}
+	return r.wait(func() bool {
+		return r.free() >= n || r.canGrow(r.length()+n)
+	}, &r.writeDeadline)
+}
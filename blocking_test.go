@@ -0,0 +1,252 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer_Blocking(t *testing.T) {
+	rb := New(4)
+	data := []byte(strings.Repeat("abcd", 64))
+
+	go func() {
+		_, _ = rb.BlockingWrite(data)
+		_ = rb.Close()
+	}()
+
+	var got []byte
+	buf := make([]byte, 3)
+	for {
+		n, err := rb.BlockingRead(buf)
+		got = append(got, buf[:n]...)
+		if errors.Is(err, ErrClosed) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("BlockingRead failed: %v", err)
+		}
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expect %d bytes of abcd but got %q", len(data), got)
+	}
+}
+
+func TestRingBuffer_WaitForData(t *testing.T) {
+	rb := New(8)
+
+	if err := rb.WaitForData(9); !errors.Is(err, ErrExceedsCapacity) {
+		t.Fatalf("expect ErrExceedsCapacity but got %v", err)
+	}
+	if err := rb.WaitForData(0); err != nil {
+		t.Fatalf("WaitForData failed: %v", err)
+	}
+
+	go func() {
+		for _, c := range []byte("abcd") {
+			time.Sleep(time.Millisecond)
+			_ = rb.WriteByte(c)
+		}
+	}()
+	if err := rb.WaitForData(4); err != nil {
+		t.Fatalf("WaitForData failed: %v", err)
+	}
+	if rb.Length() != 4 {
+		t.Fatalf("expect len 4 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		_ = rb.Close()
+	}()
+	if err := rb.WaitForData(5); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_ReadBetween(t *testing.T) {
+	rb := New(8)
+	buf := make([]byte, 6)
+
+	if _, err := rb.ReadBetween(buf[:2], 3); !errors.Is(err, io.ErrShortBuffer) {
+		t.Fatalf("expect io.ErrShortBuffer but got %v", err)
+	}
+	if _, err := rb.ReadBetween(make([]byte, 16), 9); !errors.Is(err, ErrExceedsCapacity) {
+		t.Fatalf("expect ErrExceedsCapacity but got %v", err)
+	}
+
+	written := make(chan struct{})
+	go func() {
+		for _, c := range []byte("abcdefg") {
+			time.Sleep(time.Millisecond)
+			_ = rb.WriteByte(c)
+		}
+		close(written)
+	}()
+	n, err := rb.ReadBetween(buf, 3)
+	if err != nil {
+		t.Fatalf("ReadBetween failed: %v", err)
+	}
+	if n < 3 || !bytes.Equal(buf[:n], []byte("abcdefg")[:n]) {
+		t.Fatalf("expect at least 3 bytes of abcdefg but got %s", buf[:n])
+	}
+	read := n
+
+	// the remaining bytes are returned once the buffer is closed
+	go func() {
+		<-written
+		_ = rb.Close()
+	}()
+	n, err = rb.ReadBetween(buf, 6)
+	if err != nil {
+		t.Fatalf("ReadBetween failed: %v", err)
+	}
+	if read+n != 7 {
+		t.Fatalf("expect %d bytes but got %d", 7-read, n)
+	}
+	if _, err = rb.ReadBetween(buf, 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_BlockingReset(t *testing.T) {
+	rb := New(4)
+
+	// a blocked reader re-checks the buffer and keeps waiting
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result)
+	buf := make([]byte, 4)
+	go func() {
+		n, err := rb.BlockingRead(buf)
+		done <- result{n, err}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	rb.Reset()
+	select {
+	case res := <-done:
+		t.Fatalf("expect BlockingRead to keep waiting but got %d, %v", res.n, res.err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if res := <-done; res.err != nil || !bytes.Equal(buf[:res.n], []byte("ab")) {
+		t.Fatalf("expect ab but got %s, %v", buf[:res.n], res.err)
+	}
+
+	// a blocked writer continues into the space freed by Reset
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	go func() {
+		n, err := rb.BlockingWrite([]byte("efg"))
+		done <- result{n, err}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	rb.Reset()
+	if res := <-done; res.err != nil || res.n != 3 {
+		t.Fatalf("expect write 3 bytes but got %d, %v", res.n, res.err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("efg")) {
+		t.Fatalf("expect efg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_WaitForSpace(t *testing.T) {
+	rb := New(8)
+
+	if err := rb.WaitForSpace(9); !errors.Is(err, ErrExceedsCapacity) {
+		t.Fatalf("expect ErrExceedsCapacity but got %v", err)
+	}
+	if _, err := rb.Write([]byte("abcdefgh")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	go func() {
+		for i := 0; i < 4; i++ {
+			time.Sleep(time.Millisecond)
+			_, _ = rb.ReadByte()
+		}
+	}()
+	if err := rb.WaitForSpace(4); err != nil {
+		t.Fatalf("WaitForSpace failed: %v", err)
+	}
+	if rb.Free() != 4 {
+		t.Fatalf("expect free 4 bytes but got %d. r.w=%d, r.r=%d", rb.Free(), rb.w, rb.r)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		_ = rb.Close()
+	}()
+	if err := rb.WaitForSpace(5); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+
+	// growable buffers have space up to their maximum size
+	rb = NewGrowable(4, 8)
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := rb.WaitForSpace(4); err != nil {
+		t.Fatalf("WaitForSpace failed: %v", err)
+	}
+}
+
+func TestRingBuffer_SpinWait(t *testing.T) {
+	rb := New(4, WithSpinWait(100))
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		_ = rb.WriteByte('a')
+	}()
+	buf := make([]byte, 1)
+	if _, err := rb.BlockingRead(buf); err != nil {
+		t.Fatalf("BlockingRead failed: %v", err)
+	}
+	if buf[0] != 'a' {
+		t.Fatalf("expect a but got %c", buf[0])
+	}
+}
+
+func BenchmarkRingBuffer_PingPong(b *testing.B) {
+	for _, spins := range []int{0, 100} {
+		b.Run("spins="+strconv.Itoa(spins), func(b *testing.B) {
+			ping, pong := New(1, WithSpinWait(spins)), New(1, WithSpinWait(spins))
+			go func() {
+				buf := make([]byte, 1)
+				for {
+					if _, err := ping.BlockingRead(buf); err != nil {
+						return
+					}
+					if _, err := pong.BlockingWrite(buf); err != nil {
+						return
+					}
+				}
+			}()
+			defer ping.Close()
+
+			buf := make([]byte, 1)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := ping.BlockingWrite(buf); err != nil {
+					b.Fatalf("BlockingWrite failed: %v", err)
+				}
+				if _, err := pong.BlockingRead(buf); err != nil {
+					b.Fatalf("BlockingRead failed: %v", err)
+				}
+			}
+		})
+	}
+}
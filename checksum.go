@@ -0,0 +1,40 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "hash/crc32"
+
+// WithChecksum enables a running CRC-32 checksum, computed with the given
+// table, over all bytes written to the buffer. See Checksum.
+func WithChecksum(tab *crc32.Table) Option {
+	return func(r *RingBuffer) {
+		r.crcTable = tab
+	}
+}
+
+// Checksum returns the CRC-32 checksum of all bytes written to the buffer
+// since it was created or since the last call to ResetChecksum.
+// Reading from or resetting the buffer does not affect the checksum.
+// It returns 0 if the buffer was not created with WithChecksum.
+func (r *RingBuffer) Checksum() uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.crc
+}
+
+// ResetChecksum resets the running checksum to its initial value.
+func (r *RingBuffer) ResetChecksum() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.crc = 0
+}
+
+// updateChecksum adds p to the running checksum, if enabled.
+// It must be called with r.mu held.
+func (r *RingBuffer) updateChecksum(p []byte) {
+	if r.crcTable != nil {
+		r.crc = crc32.Update(r.crc, r.crcTable, p)
+	}
+}
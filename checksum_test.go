@@ -0,0 +1,64 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"errors"
+	"hash/crc32"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer_Checksum(t *testing.T) {
+	tab := crc32.MakeTable(crc32.Castagnoli)
+	rb := New(8, WithChecksum(tab))
+
+	if rb.Checksum() != 0 {
+		t.Fatalf("expect checksum 0 but got %08x", rb.Checksum())
+	}
+
+	// write across the wrap, with a truncated write and a byte write
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := rb.WriteByte('g'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if _, err := rb.Write([]byte(strings.Repeat("h", 10))); !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+
+	want := crc32.Checksum([]byte("abcdefg"+strings.Repeat("h", 7)), tab)
+	if rb.Checksum() != want {
+		t.Fatalf("expect checksum %08x but got %08x", want, rb.Checksum())
+	}
+
+	rb.Reset()
+	if rb.Checksum() != want {
+		t.Fatalf("expect checksum %08x but got %08x", want, rb.Checksum())
+	}
+
+	rb.ResetChecksum()
+	if _, err := rb.Write([]byte("xyz")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	want = crc32.Checksum([]byte("xyz"), tab)
+	if rb.Checksum() != want {
+		t.Fatalf("expect checksum %08x but got %08x", want, rb.Checksum())
+	}
+}
+
+func TestRingBuffer_ChecksumDisabled(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abc")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.Checksum() != 0 {
+		t.Fatalf("expect checksum 0 but got %08x", rb.Checksum())
+	}
+}
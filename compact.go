@@ -0,0 +1,43 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// Compact moves the unread data to the start of the underlying buffer,
+// so that it is contiguous and the read position is zero.
+// It runs in time proportional to the size of the buffer and does not
+// allocate. It is a no-op if the read position is already zero, or if
+// segments returned by DrainSegments have not been released.
+func (r *RingBuffer) Compact() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pinned == 0 {
+		r.compact()
+	}
+}
+
+// compact is Compact without locking. It must be called with r.mu held.
+func (r *RingBuffer) compact() {
+	if r.r == 0 {
+		return
+	}
+	if r.w == r.r && !r.isFull {
+		r.r, r.w = 0, 0
+		r.replay = 0
+		return
+	}
+
+	// Rotate the whole buffer left by r.r.
+	reverse(r.buf[:r.r])
+	reverse(r.buf[r.r:])
+	reverse(r.buf)
+	r.w = (r.w - r.r + r.size) % r.size
+	r.r = 0
+}
+
+func reverse(p []byte) {
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRingBuffer_Compact(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		read     int
+		write    string
+		expected string
+	}{
+		{"empty", 6, "", ""},
+		{"contiguous", 2, "", "cdef"},
+		{"wrapped", 4, "ghij", "efghij"},
+		{"full", 4, "ghijkl", "efghijkl"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rb := New(8)
+			if _, err := rb.Write([]byte("abcdef")); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if _, err := rb.Read(make([]byte, tc.read)); err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			if _, err := rb.Write([]byte(tc.write)); err != nil && tc.write != "" {
+				t.Fatalf("write failed: %v", err)
+			}
+
+			rb.Compact()
+			if rb.r != 0 {
+				t.Fatalf("expect r.r=0 but got %d. r.w=%d", rb.r, rb.w)
+			}
+			if rb.Length() != len(tc.expected) {
+				t.Fatalf("expect len %d bytes but got %d. r.w=%d, r.r=%d", len(tc.expected), rb.Length(), rb.w, rb.r)
+			}
+			if !bytes.Equal(rb.buf[:len(tc.expected)], []byte(tc.expected)) {
+				t.Fatalf("expect %s at the start of the buffer but got %s", tc.expected, rb.buf)
+			}
+			if !bytes.Equal(rb.Bytes(), []byte(tc.expected)) && tc.expected != "" {
+				t.Fatalf("expect %s but got %s. r.w=%d, r.r=%d", tc.expected, rb.Bytes(), rb.w, rb.r)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// NewConnPair returns the two ends of an in-memory, full duplex connection.
+// Unlike net.Pipe, each direction is buffered by a RingBuffer of the given
+// size, so writes only block once the peer has fallen size bytes behind.
+//
+// Reads return io.EOF once the peer has closed the connection and all data
+// written before then has been read. Reads and writes on a connection that
+// has been closed locally return io.ErrClosedPipe.
+func NewConnPair(size int) (net.Conn, net.Conn) {
+	ab, ba := New(size), New(size)
+	return &conn{rd: ba, wr: ab}, &conn{rd: ab, wr: ba}
+}
+
+type conn struct {
+	rd     *RingBuffer
+	wr     *RingBuffer
+	closed atomic.Bool
+}
+
+func (c *conn) Read(p []byte) (int, error) {
+	if c.closed.Load() {
+		return 0, io.ErrClosedPipe
+	}
+	n, err := c.rd.BlockingRead(p)
+	if errors.Is(err, ErrClosed) {
+		if c.closed.Load() {
+			return n, io.ErrClosedPipe
+		}
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (c *conn) Write(p []byte) (int, error) {
+	if c.closed.Load() {
+		return 0, io.ErrClosedPipe
+	}
+	n, err := c.wr.BlockingWrite(p)
+	if errors.Is(err, ErrClosed) {
+		return n, io.ErrClosedPipe
+	}
+	return n, err
+}
+
+func (c *conn) Close() error {
+	c.closed.Store(true)
+	_ = c.rd.Close()
+	return c.wr.Close()
+}
+
+func (c *conn) LocalAddr() net.Addr  { return connAddr{} }
+func (c *conn) RemoteAddr() net.Addr { return connAddr{} }
+
+func (c *conn) SetDeadline(t time.Time) error {
+	_ = c.SetReadDeadline(t)
+	return c.SetWriteDeadline(t)
+}
+
+func (c *conn) SetReadDeadline(t time.Time) error {
+	return c.rd.SetReadDeadline(t)
+}
+
+func (c *conn) SetWriteDeadline(t time.Time) error {
+	return c.wr.SetWriteDeadline(t)
+}
+
+// connAddr is the address of both ends of a connection from NewConnPair.
+type connAddr struct{}
+
+func (connAddr) Network() string { return "ringbuffer" }
+func (connAddr) String() string  { return "ringbuffer" }
@@ -0,0 +1,82 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnPair(t *testing.T) {
+	a, b := NewConnPair(16)
+	data := []byte(strings.Repeat("abcd", 64))
+
+	// writes are buffered up to the size of the buffer
+	if _, err := a.Write([]byte("ping")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(b, buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(buf) != "ping" {
+		t.Fatalf("expect ping but got %q", buf)
+	}
+
+	go func() {
+		_, _ = b.Write(data)
+		_ = b.Close()
+	}()
+	got, err := io.ReadAll(a)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expect %d bytes of abcd but got %q", len(data), got)
+	}
+
+	if _, err := a.Write([]byte("x")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("expect io.ErrClosedPipe but got %v", err)
+	}
+	_ = a.Close()
+	if _, err := a.Read(buf); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("expect io.ErrClosedPipe but got %v", err)
+	}
+}
+
+func TestConnPair_Deadline(t *testing.T) {
+	a, b := NewConnPair(4)
+	defer a.Close()
+	defer b.Close()
+
+	if err := a.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("SetReadDeadline failed: %v", err)
+	}
+	_, err := a.Read(make([]byte, 1))
+	var nerr net.Error
+	if !errors.As(err, &nerr) || !nerr.Timeout() {
+		t.Fatalf("expect a timeout error but got %v", err)
+	}
+
+	if err := a.SetWriteDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("SetWriteDeadline failed: %v", err)
+	}
+	n, err := a.Write([]byte("abcdef"))
+	if !errors.As(err, &nerr) || !nerr.Timeout() {
+		t.Fatalf("expect a timeout error but got %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("expect write 4 bytes but got %d", n)
+	}
+
+	if a.LocalAddr().Network() != "ringbuffer" {
+		t.Fatalf("expect network ringbuffer but got %s", a.LocalAddr().Network())
+	}
+}
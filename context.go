@@ -0,0 +1,82 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"context"
+	"time"
+)
+
+// ReadContext is like BlockingRead, but also returns ctx.Err() if ctx is
+// done before any data is available.
+func (r *RingBuffer) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	err = r.waitContext(ctx, r.readable, &r.readDeadline)
+	if err == nil {
+		n, err = r.read(r.chunk(p, 0))
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
+
+// ReadByteContext reads one byte, waiting until one is available.
+// It returns ctx.Err() if ctx is done first, ErrClosed if the buffer is
+// closed and empty, and ErrTimeout if the read deadline passes first.
+// Each call can be given its own timeout with context.WithTimeout, which
+// suits byte-at-a-time protocol parsers.
+func (r *RingBuffer) ReadByteContext(ctx context.Context) (b byte, err error) {
+	r.mu.Lock()
+	err = r.waitContext(ctx, r.readable, &r.readDeadline)
+	if err == nil {
+		b, err = r.readByte()
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return b, err
+}
+
+// WaitEmpty blocks until all data in the buffer has been read.
+// It returns ctx.Err() if ctx is done, or ErrClosed if the buffer is closed,
+// before then. For a graceful shutdown, a producer can stop writing, wait
+// for the consumer to drain the buffer with WaitEmpty, and then Close it.
+func (r *RingBuffer) WaitEmpty(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.waitContext(ctx, func() bool {
+		return !r.readable()
+	}, &time.Time{})
+}
+
+// WaitFull blocks until the buffer is full, so that a batch consumer can
+// process a full buffer's worth of data at once, for example with
+// DrainSegments. It returns ctx.Err() if ctx is done, or ErrClosed if the
+// buffer is closed, before then. It returns ErrExceedsCapacity immediately
+// if the buffer can't hold any data.
+// A buffer created by NewGrowable is full when it is full at its current
+// size.
+func (r *RingBuffer) WaitFull(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size == 0 {
+		return ErrExceedsCapacity
+	}
+	return r.waitContext(ctx, func() bool {
+		return r.isFull
+	}, &time.Time{})
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer_ReadContext(t *testing.T) {
+	rb := New(8)
+	buf := make([]byte, 4)
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		_, _ = rb.Write([]byte("ab"))
+	}()
+	n, err := rb.ReadContext(context.Background(), buf)
+	if err != nil {
+		t.Fatalf("ReadContext failed: %v", err)
+	}
+	if !bytes.Equal(buf[:n], []byte("ab")) {
+		t.Fatalf("expect ab but got %s", buf[:n])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond)
+		cancel()
+	}()
+	if _, err := rb.ReadContext(ctx, buf); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context.Canceled but got %v", err)
+	}
+}
+
+func TestRingBuffer_ReadByteContext(t *testing.T) {
+	rb := New(8)
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		_ = rb.WriteByte('a')
+	}()
+	b, err := rb.ReadByteContext(context.Background())
+	if err != nil {
+		t.Fatalf("ReadByteContext failed: %v", err)
+	}
+	if b != 'a' {
+		t.Fatalf("expect a but got %c", b)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := rb.ReadByteContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect context.DeadlineExceeded but got %v", err)
+	}
+
+	// data already buffered is returned even if ctx is done
+	if err := rb.WriteByte('b'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if b, err := rb.ReadByteContext(ctx); err != nil || b != 'b' {
+		t.Fatalf("expect b but got %c, %v", b, err)
+	}
+
+	_ = rb.Close()
+	if _, err := rb.ReadByteContext(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_WaitEmpty(t *testing.T) {
+	rb := New(8)
+	if err := rb.WaitEmpty(context.Background()); err != nil {
+		t.Fatalf("WaitEmpty failed on an empty buffer: %v", err)
+	}
+
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	go func() {
+		buf := make([]byte, 2)
+		for i := 0; i < 2; i++ {
+			time.Sleep(time.Millisecond)
+			_, _ = rb.Read(buf)
+		}
+	}()
+	if err := rb.WaitEmpty(context.Background()); err != nil {
+		t.Fatalf("WaitEmpty failed: %v", err)
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect empty buffer but got %d bytes", rb.Length())
+	}
+
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := rb.WaitEmpty(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect context.DeadlineExceeded but got %v", err)
+	}
+}
+
+func TestRingBuffer_WaitFull(t *testing.T) {
+	rb := New(8)
+
+	go func() {
+		for _, s := range []string{"abc", "def", "gh"} {
+			time.Sleep(time.Millisecond)
+			_, _ = rb.Write([]byte(s))
+		}
+	}()
+	if err := rb.WaitFull(context.Background()); err != nil {
+		t.Fatalf("WaitFull failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcdefgh")) {
+		t.Fatalf("expect abcdefgh but got %s", rb.Bytes())
+	}
+
+	if _, err := rb.Read(make([]byte, 1)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := rb.WaitFull(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect context.DeadlineExceeded but got %v", err)
+	}
+
+	_ = rb.Close()
+	if err := rb.WaitFull(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+
+	if err := New(0).WaitFull(context.Background()); !errors.Is(err, ErrExceedsCapacity) {
+		t.Fatalf("expect ErrExceedsCapacity but got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "unsafe"
+
+// CopyTo moves as many unread bytes as fit from the buffer into dst,
+// consuming them from this buffer. It returns the number of bytes moved,
+// and ErrFull if dst could not take all of the unread data.
+// Both buffers are locked for the duration of the transfer, in a consistent
+// order, so concurrent transfers between the same buffers don't deadlock.
+func (r *RingBuffer) CopyTo(dst *RingBuffer) (n int, err error) {
+	if dst == r {
+		return 0, nil
+	}
+
+	unlock := lockPair(r, dst)
+	first, second := r.segments()
+	for _, seg := range [][]byte{first, second} {
+		if len(seg) == 0 {
+			continue
+		}
+		m, werr := dst.write(seg)
+		r.consumed(seg[:m])
+		n += m
+		if werr != nil {
+			err = werr
+			break
+		}
+	}
+	r.advance(n)
+	if err == ErrShortWrite {
+		err = ErrFull
+	}
+	emptyHook, fullHook := r.emptyHook(), dst.fullHook()
+	unlock()
+
+	if emptyHook != nil {
+		emptyHook()
+	}
+	if fullHook != nil {
+		fullHook()
+	}
+	return n, err
+}
+
+// lockPair locks a and b in address order, so that goroutines locking the
+// same pair of buffers can't deadlock, and returns a function that unlocks
+// both. a and b must be different buffers.
+func lockPair(a, b *RingBuffer) (unlock func()) {
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	a.mu.Lock()
+	b.mu.Lock()
+	return func() {
+		b.mu.Unlock()
+		a.mu.Unlock()
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRingBuffer_CopyTo(t *testing.T) {
+	src, dst := New(8), New(6)
+
+	// wrap the source data around the end of its buffer
+	if _, err := src.Write([]byte("xxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := src.Read(make([]byte, 5)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := src.Write([]byte("abcdefg")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := dst.Write([]byte("12")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	n, err := src.CopyTo(dst)
+	if !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("expect copy 4 bytes but got %d", n)
+	}
+	if !bytes.Equal(dst.Bytes(), []byte("12abcd")) {
+		t.Fatalf("expect 12abcd but got %s. r.w=%d, r.r=%d", dst.Bytes(), dst.w, dst.r)
+	}
+	if !bytes.Equal(src.Bytes(), []byte("efg")) {
+		t.Fatalf("expect efg but got %s. r.w=%d, r.r=%d", src.Bytes(), src.w, src.r)
+	}
+
+	if _, err := dst.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	n, err = src.CopyTo(dst)
+	if err != nil {
+		t.Fatalf("CopyTo failed: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expect copy 3 bytes but got %d", n)
+	}
+	if !src.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false")
+	}
+}
+
+func TestRingBuffer_CopyToConcurrent(t *testing.T) {
+	a, b := New(64), New(64)
+	if _, err := a.Write(bytes.Repeat([]byte("a"), 32)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := b.Write(bytes.Repeat([]byte("b"), 32)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// transfers in opposite directions take the locks in the same order
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_, _ = a.CopyTo(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_, _ = b.CopyTo(a)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if a.Length()+b.Length() != 64 {
+		t.Fatalf("expect 64 bytes in total but got %d", a.Length()+b.Length())
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrTimeout is returned by blocking reads and writes when their deadline
+// passes. It implements net.Error, with Timeout returning true, and wraps
+// os.ErrDeadlineExceeded.
+var ErrTimeout error = timeoutError{}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "ringbuffer i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+func (timeoutError) Unwrap() error   { return os.ErrDeadlineExceeded }
+
+// SetReadDeadline sets the deadline for blocking reads, such as BlockingRead.
+// Once the deadline passes, blocked and future blocking reads return
+// ErrTimeout. A zero value for t means blocking reads will not time out.
+// Non-blocking reads such as Read are not affected.
+func (r *RingBuffer) SetReadDeadline(t time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readDeadline = t
+	r.readTimer = r.resetTimer(r.readTimer, t)
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for blocking writes, such as
+// BlockingWrite. Once the deadline passes, blocked and future blocking writes
+// return ErrTimeout. A zero value for t means blocking writes will not time
+// out. Non-blocking writes such as Write are not affected.
+func (r *RingBuffer) SetWriteDeadline(t time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writeDeadline = t
+	r.writeTimer = r.resetTimer(r.writeTimer, t)
+	return nil
+}
+
+// SetDeadline sets both the read and write deadlines.
+func (r *RingBuffer) SetDeadline(t time.Time) error {
+	_ = r.SetReadDeadline(t)
+	return r.SetWriteDeadline(t)
+}
+
+// resetTimer stops timer, if any, and returns a new timer that wakes
+// waiting goroutines at t, or nil if t is zero.
+// It must be called with r.mu held.
+func (r *RingBuffer) resetTimer(timer *time.Timer, t time.Time) *time.Timer {
+	if timer != nil {
+		timer.Stop()
+	}
+	// Wake waiters so they re-check the new deadline.
+	r.cond.Broadcast()
+	if t.IsZero() {
+		return nil
+	}
+	return time.AfterFunc(time.Until(t), func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.cond.Broadcast()
+	})
+}
+
+// ReadTimeout is like BlockingRead, but returns ErrTimeout if no data is
+// available within d, as well as at the read deadline.
+// If d is zero or negative, it doesn't block, and is the same as Read.
+func (r *RingBuffer) ReadTimeout(p []byte, d time.Duration) (int, error) {
+	if d <= 0 {
+		return r.Read(p)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	n, err := r.ReadContext(ctx, p)
+	return n, timeoutErr(err)
+}
+
+// WriteTimeout is like BlockingWrite, but returns ErrTimeout if all of p
+// has not been written within d, as well as at the write deadline.
+// On timeout it returns the number of bytes written.
+// If d is zero or negative, it doesn't block, and is the same as Write.
+func (r *RingBuffer) WriteTimeout(p []byte, d time.Duration) (n int, err error) {
+	if d <= 0 {
+		return r.Write(p)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	r.mu.Lock()
+	n, err = r.blockingWrite(ctx, p)
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, timeoutErr(err)
+}
+
+// ReadByteTimeout is like ReadByteContext, but returns ErrTimeout if no byte
+// is available within d, as well as at the read deadline.
+// If d is zero or negative, it doesn't block, and is the same as ReadByte.
+func (r *RingBuffer) ReadByteTimeout(d time.Duration) (byte, error) {
+	if d <= 0 {
+		return r.ReadByte()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	b, err := r.ReadByteContext(ctx)
+	return b, timeoutErr(err)
+}
+
+// timeoutErr replaces the error from a context that timed out with ErrTimeout.
+func timeoutErr(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return err
+}
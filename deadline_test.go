@@ -0,0 +1,172 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer_ReadDeadline(t *testing.T) {
+	rb := New(4)
+
+	if err := rb.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("SetReadDeadline failed: %v", err)
+	}
+	n, err := rb.BlockingRead(make([]byte, 1))
+	var nerr net.Error
+	if !errors.As(err, &nerr) || !nerr.Timeout() {
+		t.Fatalf("expect a timeout error but got %v", err)
+	}
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expect os.ErrDeadlineExceeded but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect read 0 bytes but got %d", n)
+	}
+
+	// data is returned even after the deadline has passed
+	if err := rb.WriteByte('a'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if n, err := rb.BlockingRead(make([]byte, 1)); err != nil || n != 1 {
+		t.Fatalf("expect read 1 byte but got %d, %v", n, err)
+	}
+
+	// a zero deadline clears it
+	if err := rb.SetReadDeadline(time.Time{}); err != nil {
+		t.Fatalf("SetReadDeadline failed: %v", err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = rb.WriteByte('b')
+	}()
+	if n, err := rb.BlockingRead(make([]byte, 1)); err != nil || n != 1 {
+		t.Fatalf("expect read 1 byte but got %d, %v", n, err)
+	}
+}
+
+func TestRingBuffer_WriteDeadline(t *testing.T) {
+	rb := New(4)
+
+	// the deadline is set while the writer is blocked
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = rb.SetWriteDeadline(time.Now())
+	}()
+	n, err := rb.BlockingWrite([]byte("abcdef"))
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expect ErrTimeout but got %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("expect write 4 bytes but got %d", n)
+	}
+
+	// non-blocking writes are not affected
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func TestRingBuffer_ReadTimeout(t *testing.T) {
+	rb := New(4)
+	buf := make([]byte, 4)
+
+	n, err := rb.ReadTimeout(buf, 5*time.Millisecond)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expect a timeout net.Error but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect read 0 bytes but got %d", n)
+	}
+
+	// try once
+	if _, err := rb.ReadTimeout(buf, 0); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n, err := rb.ReadTimeout(buf, 0); err != nil || n != 2 {
+		t.Fatalf("expect read 2 bytes but got %d, %v", n, err)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		_ = rb.WriteByte('c')
+	}()
+	if n, err := rb.ReadTimeout(buf, time.Second); err != nil || n != 1 {
+		t.Fatalf("expect read 1 byte but got %d, %v", n, err)
+	}
+}
+
+func TestRingBuffer_WriteTimeout(t *testing.T) {
+	rb := New(4)
+
+	n, err := rb.WriteTimeout([]byte("abcdef"), 5*time.Millisecond)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expect ErrTimeout but got %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("expect write 4 bytes but got %d", n)
+	}
+
+	// try once
+	if _, err := rb.Read(make([]byte, 1)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	n, err = rb.WriteTimeout([]byte("ef"), -1)
+	if !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expect write 1 byte but got %d", n)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		_, _ = rb.Read(make([]byte, 4))
+	}()
+	if n, err := rb.WriteTimeout([]byte("gh"), time.Second); err != nil || n != 2 {
+		t.Fatalf("expect write 2 bytes but got %d, %v", n, err)
+	}
+}
+
+func TestRingBuffer_ReadByteTimeout(t *testing.T) {
+	rb := New(4)
+
+	_, err := rb.ReadByteTimeout(5 * time.Millisecond)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expect a timeout net.Error but got %v", err)
+	}
+	if _, err := rb.ReadByteTimeout(0); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	// each byte gets its own timeout
+	go func() {
+		for _, c := range []byte("abc") {
+			time.Sleep(2 * time.Millisecond)
+			_ = rb.WriteByte(c)
+		}
+	}()
+	for _, want := range []byte("abc") {
+		b, err := rb.ReadByteTimeout(time.Second)
+		if err != nil {
+			t.Fatalf("ReadByteTimeout failed: %v", err)
+		}
+		if b != want {
+			t.Fatalf("expect %c but got %c", want, b)
+		}
+	}
+}
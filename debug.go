@@ -0,0 +1,10 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build debug
+
+package ringbuffer
+
+// debug enables checking the buffer's invariants after every change.
+const debug = true
@@ -0,0 +1,117 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "bytes"
+
+// HasDelim reports whether delim occurs in the unread data.
+func (r *RingBuffer) HasDelim(delim byte) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.indexByte(delim) >= 0
+}
+
+// ReadBytes reads until the first occurrence of delim in the unread data,
+// returning a slice containing the data up to and including the delimiter.
+// If delim is not buffered yet, it returns ErrEmpty without consuming
+// anything, so the caller can retry once more data has been written.
+// If the buffer is closed, it returns ErrClosed instead; any remaining data
+// can still be consumed with Read.
+func (r *RingBuffer) ReadBytes(delim byte) ([]byte, error) {
+	r.mu.Lock()
+	p, err := r.readBytes(delim)
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return p, err
+}
+
+// readBytes is ReadBytes without locking. It must be called with r.mu held.
+func (r *RingBuffer) readBytes(delim byte) ([]byte, error) {
+	i := r.indexByte(delim)
+	if i < 0 {
+		return nil, r.emptyErr()
+	}
+	p := make([]byte, i+1)
+	_, _ = r.read(p)
+	return p, nil
+}
+
+// ReadBytesUpTo is like ReadBytes, but returns ErrTokenTooLong if delim
+// does not occur within the first max unread bytes, so that a peer that
+// never sends the delimiter can't make the caller buffer unbounded data.
+// The returned slice, including the delimiter, is at most max bytes long.
+// It consumes nothing when it returns an error, so on ErrTokenTooLong the
+// caller can decide whether to discard the data or fail.
+// It returns ErrOutOfRange if max is not positive.
+func (r *RingBuffer) ReadBytesUpTo(max int, delim byte) ([]byte, error) {
+	if max <= 0 {
+		return nil, ErrOutOfRange
+	}
+
+	r.mu.Lock()
+	var p []byte
+	var err error
+	i := r.indexByte(delim)
+	if r.length() == 0 {
+		err = r.emptyErr()
+	} else if i >= max || (i < 0 && r.length() >= max) {
+		err = ErrTokenTooLong
+	} else {
+		p, err = r.readBytes(delim)
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return p, err
+}
+
+// ReadString is like ReadBytes but returns a string.
+func (r *RingBuffer) ReadString(delim byte) (string, error) {
+	p, err := r.ReadBytes(delim)
+	return string(p), err
+}
+
+// indexByte returns the offset of the first occurrence of c in the unread
+// data, or -1 if it is not present. It must be called with r.mu held.
+func (r *RingBuffer) indexByte(c byte) int {
+	n := r.length()
+	if n == 0 {
+		return -1
+	}
+	if r.r+n <= r.size {
+		return bytes.IndexByte(r.buf[r.r:r.r+n], c)
+	}
+
+	c1 := r.size - r.r
+	if i := bytes.IndexByte(r.buf[r.r:], c); i >= 0 {
+		return i
+	}
+	if i := bytes.IndexByte(r.buf[:n-c1], c); i >= 0 {
+		return c1 + i
+	}
+	return -1
+}
+
+// ReadLine reads a line terminated by "\n" or "\r\n" and returns it
+// without the terminator. If a complete line is not buffered yet, it returns
+// ErrEmpty without consuming anything, or ErrClosed if the buffer is closed.
+func (r *RingBuffer) ReadLine() ([]byte, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = line[:len(line)-1]
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_ReadBytes(t *testing.T) {
+	rb := New(8)
+
+	if rb.HasDelim('\n') {
+		t.Fatalf("expect HasDelim is false but got true")
+	}
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("xxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 5)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ab;cd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if !rb.HasDelim(';') {
+		t.Fatalf("expect HasDelim is true but got false")
+	}
+	p, err := rb.ReadBytes(';')
+	if err != nil {
+		t.Fatalf("ReadBytes failed: %v", err)
+	}
+	if string(p) != "ab;" {
+		t.Fatalf("expect ab; but got %q", p)
+	}
+
+	// delimiter not buffered yet
+	if rb.HasDelim(';') {
+		t.Fatalf("expect HasDelim is false but got true")
+	}
+	if _, err := rb.ReadString(';'); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if rb.Length() != 2 {
+		t.Fatalf("expect len 2 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	// delimiter in the wrapped part
+	if _, err := rb.Write([]byte("ef;")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	s, err := rb.ReadString(';')
+	if err != nil {
+		t.Fatalf("ReadString failed: %v", err)
+	}
+	if s != "cdef;" {
+		t.Fatalf("expect cdef; but got %q", s)
+	}
+
+	_ = rb.Close()
+	if _, err := rb.ReadString(';'); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_ReadLine(t *testing.T) {
+	rb := New(16)
+
+	// wrap a line around the end of the buffer
+	if _, err := rb.Write([]byte("xxxxxxxxxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 12)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("one\r\ntwo\n\nthr")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	for _, want := range []string{"one", "two", ""} {
+		line, err := rb.ReadLine()
+		if err != nil {
+			t.Fatalf("ReadLine failed: %v", err)
+		}
+		if string(line) != want {
+			t.Fatalf("expect %q but got %q", want, line)
+		}
+	}
+
+	if _, err := rb.ReadLine(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if rb.Length() != 3 {
+		t.Fatalf("expect len 3 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+	if _, err := rb.Write([]byte("ee\r\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	line, err := rb.ReadLine()
+	if err != nil {
+		t.Fatalf("ReadLine failed: %v", err)
+	}
+	if string(line) != "three" {
+		t.Fatalf("expect three but got %q", line)
+	}
+}
+
+func TestRingBuffer_ReadBytesUpTo(t *testing.T) {
+	rb := New(16)
+
+	if _, err := rb.ReadBytesUpTo(4, '\n'); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	for _, max := range []int{0, -1} {
+		if _, err := rb.ReadBytesUpTo(max, '\n'); !errors.Is(err, ErrOutOfRange) {
+			t.Fatalf("expect ErrOutOfRange for max %d but got %v", max, err)
+		}
+	}
+
+	if _, err := rb.Write([]byte("ab\ncdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	p, err := rb.ReadBytesUpTo(3, '\n')
+	if err != nil || !bytes.Equal(p, []byte("ab\n")) {
+		t.Fatalf("expect ab\\n but got %q, %v", p, err)
+	}
+
+	// fewer than max bytes without the delimiter may still complete
+	if _, err := rb.ReadBytesUpTo(5, '\n'); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.ReadBytesUpTo(4, '\n'); !errors.Is(err, ErrTokenTooLong) {
+		t.Fatalf("expect ErrTokenTooLong but got %v", err)
+	}
+
+	// the delimiter is buffered, but past max
+	if _, err := rb.Write([]byte("\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.ReadBytesUpTo(4, '\n'); !errors.Is(err, ErrTokenTooLong) {
+		t.Fatalf("expect ErrTokenTooLong but got %v", err)
+	}
+	if rb.Length() != 5 {
+		t.Fatalf("expect len 5 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+	if p, err = rb.ReadBytesUpTo(5, '\n'); err != nil || !bytes.Equal(p, []byte("cdef\n")) {
+		t.Fatalf("expect cdef\\n but got %q, %v", p, err)
+	}
+
+	_ = rb.Close()
+	if _, err := rb.ReadBytesUpTo(1, '\n'); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_ReadBytesUpToEOF(t *testing.T) {
+	rb := New(8, WithEOFOnEmpty())
+	if _, err := rb.ReadBytesUpTo(1, '\n'); !errors.Is(err, io.EOF) {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+}
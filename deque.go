@@ -0,0 +1,115 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// ReadByteBack removes and returns the most recently written unread byte,
+// so that the buffer can be used as a double-ended queue together with
+// ReadByte, WriteByte and WriteByteFront.
+// It returns ErrEmpty if the buffer is empty, or ErrClosed if it is
+// also closed.
+func (r *RingBuffer) ReadByteBack() (byte, error) {
+	r.mu.Lock()
+	b, err := r.readByteBack()
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return b, err
+}
+
+// readByteBack is ReadByteBack without locking. It must be called with r.mu held.
+func (r *RingBuffer) readByteBack() (byte, error) {
+	if r.w == r.r && !r.isFull {
+		return 0, r.emptyErr()
+	}
+	r.w = (r.w - 1 + r.size) % r.size
+	r.isFull = false
+	b := r.buf[r.w]
+	r.consumed(r.buf[r.w : r.w+1])
+	r.changed()
+	return b, nil
+}
+
+// Front returns the oldest unread byte, the next one ReadByte would return,
+// without consuming it. It returns ErrEmpty if the buffer is empty, or
+// ErrClosed if it is also closed.
+func (r *RingBuffer) Front() (byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.w == r.r && !r.isFull {
+		return 0, r.emptyErr()
+	}
+	return r.buf[r.r], nil
+}
+
+// Back returns the most recently written unread byte, the one ReadByteBack
+// would return, without consuming it. It returns ErrEmpty if the buffer is
+// empty, or ErrClosed if it is also closed.
+func (r *RingBuffer) Back() (byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.w == r.r && !r.isFull {
+		return 0, r.emptyErr()
+	}
+	return r.buf[(r.w-1+r.size)%r.size], nil
+}
+
+// WriteByteFront writes c before the unread bytes, so that it is the next
+// byte returned by ReadByte. It returns ErrFull if the buffer is full.
+// The byte overwrites the most recently read byte, which Rewind can then
+// no longer return to.
+func (r *RingBuffer) WriteByteFront(c byte) error {
+	r.mu.Lock()
+	err := r.writeByteFront(c)
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
+
+// writeByteFront is WriteByteFront without locking. It must be called with r.mu held.
+func (r *RingBuffer) writeByteFront(c byte) error {
+	if r.closed {
+		return ErrClosed
+	}
+	if r.free() == 0 && r.size < r.maxSize {
+		r.grow(r.size + 1)
+	}
+	if r.free() == 0 {
+		return ErrFull
+	}
+	r.r = (r.r - 1 + r.size) % r.size
+	r.buf[r.r] = c
+	r.updateChecksum(r.buf[r.r : r.r+1])
+	if r.replay > 0 {
+		r.replay--
+	}
+	if r.w == r.r {
+		r.isFull = true
+	}
+	r.changed()
+	r.signal()
+
+	return nil
+}
+
+// ReverseBytes returns a copy of the unread bytes, newest first,
+// without changing the read pointer. It returns nil if the buffer is empty.
+// To consume bytes newest first, use ReadByteBack.
+func (r *RingBuffer) ReverseBytes() []byte {
+	r.mu.Lock()
+	p := r.bytes()
+	r.mu.Unlock()
+
+	reverse(p)
+	return p
+}
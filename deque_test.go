@@ -0,0 +1,164 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRingBuffer_Deque(t *testing.T) {
+	rb := New(4)
+
+	if _, err := rb.ReadByteBack(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	// r and w both at 0: pushing to the front wraps r to the end
+	if err := rb.WriteByteFront('b'); err != nil {
+		t.Fatalf("WriteByteFront failed: %v", err)
+	}
+	if err := rb.WriteByteFront('a'); err != nil {
+		t.Fatalf("WriteByteFront failed: %v", err)
+	}
+	if err := rb.WriteByte('c'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if err := rb.WriteByte('d'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+	if err := rb.WriteByteFront('x'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcd")) {
+		t.Fatalf("expect abcd but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	b, err := rb.ReadByteBack()
+	if err != nil {
+		t.Fatalf("ReadByteBack failed: %v", err)
+	}
+	if b != 'd' {
+		t.Fatalf("expect d but got %c", b)
+	}
+	if rb.IsFull() {
+		t.Fatalf("expect IsFull is false but got true")
+	}
+	if b, _ = rb.ReadByte(); b != 'a' {
+		t.Fatalf("expect a but got %c", b)
+	}
+	if b, _ = rb.ReadByteBack(); b != 'c' {
+		t.Fatalf("expect c but got %c", b)
+	}
+	if b, _ = rb.ReadByteBack(); b != 'b' {
+		t.Fatalf("expect b but got %c", b)
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+
+	_ = rb.Close()
+	if _, err := rb.ReadByteBack(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+	if err := rb.WriteByteFront('a'); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_WriteByteFrontRewind(t *testing.T) {
+	rb := New(4)
+	if _, err := rb.Write([]byte("abc")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 2)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	// 'x' overwrites 'b'
+	if err := rb.WriteByteFront('x'); err != nil {
+		t.Fatalf("WriteByteFront failed: %v", err)
+	}
+	if err := rb.Rewind(2); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	if err := rb.Rewind(1); err != nil {
+		t.Fatalf("rewind failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("axc")) {
+		t.Fatalf("expect axc but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_ReverseBytes(t *testing.T) {
+	rb := New(8)
+	if p := rb.ReverseBytes(); p != nil {
+		t.Fatalf("expect nil but got %s", p)
+	}
+
+	if _, err := rb.Write([]byte("xxxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("abcdefgh")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if p := rb.ReverseBytes(); !bytes.Equal(p, []byte("hgfedcba")) {
+		t.Fatalf("expect hgfedcba but got %s. r.w=%d, r.r=%d", p, rb.w, rb.r)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcdefgh")) {
+		t.Fatalf("expect abcdefgh but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_FrontBack(t *testing.T) {
+	rb := New(8)
+
+	if _, err := rb.Front(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.Back(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("gh")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// the write position has wrapped to 0
+	if b, err := rb.Front(); err != nil || b != 'e' {
+		t.Fatalf("expect e but got %c, %v. r.w=%d, r.r=%d", b, err, rb.w, rb.r)
+	}
+	if b, err := rb.Back(); err != nil || b != 'h' {
+		t.Fatalf("expect h but got %c, %v. r.w=%d, r.r=%d", b, err, rb.w, rb.r)
+	}
+	if _, err := rb.Write([]byte("ijkl")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if b, err := rb.Back(); err != nil || b != 'l' {
+		t.Fatalf("expect l but got %c, %v. r.w=%d, r.r=%d", b, err, rb.w, rb.r)
+	}
+	if rb.Length() != 8 {
+		t.Fatalf("expect len 8 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	rb.Reset()
+	_ = rb.Close()
+	if _, err := rb.Back(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// DrainSegments returns all the unread data as up to two slices of the
+// underlying buffer, in order, without copying it. The second slice is nil
+// unless the data wraps around the end of the underlying buffer.
+// The data is consumed when release is called, not before, so that writes
+// can't overwrite it while the slices are in use: until then, writes that
+// don't fit return ErrFull or ErrShortWrite even in overwrite mode, and
+// Compact does nothing. Calling release again, or after a later call to
+// DrainSegments, has no effect.
+//
+// The caller must not modify the slices, must stop using them once it calls
+// release, and must not read from the buffer in the meantime. Reset and Swap
+// invalidate the slices, after which release does nothing.
+func (r *RingBuffer) DrainSegments() (first, second []byte, release func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first, second = r.segments()
+	r.pinned = len(first) + len(second)
+	r.drains++
+	drain := r.drains
+	return first, second, func() { r.release(drain) }
+}
+
+// release consumes the bytes held by the given call to DrainSegments, if
+// they are still held.
+func (r *RingBuffer) release(drain uint64) {
+	r.mu.Lock()
+	if n := r.pinned; n > 0 && drain == r.drains {
+		first, second := r.segments()
+		if len(first) > n {
+			first, second = first[:n], nil
+		} else if len(first)+len(second) > n {
+			second = second[:n-len(first)]
+		}
+		r.consumed(first)
+		r.consumed(second)
+		r.advance(n)
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+}
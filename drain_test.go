@@ -0,0 +1,123 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRingBuffer_DrainSegments(t *testing.T) {
+	rb := New(8, WithOverwrite())
+
+	first, second, release := rb.DrainSegments()
+	if first != nil || second != nil {
+		t.Fatalf("expect no segments but got %s and %s", first, second)
+	}
+	release()
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	first, second, release = rb.DrainSegments()
+	if !bytes.Equal(first, []byte("efgh")) || !bytes.Equal(second, []byte("ij")) {
+		t.Fatalf("expect efgh and ij but got %s and %s", first, second)
+	}
+
+	// held bytes are not overwritten
+	n, err := rb.Write([]byte("klmn"))
+	if !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expect write 2 bytes but got %d", n)
+	}
+	if err := rb.WriteByte('o'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	rb.Compact()
+	if !bytes.Equal(first, []byte("efgh")) || !bytes.Equal(second, []byte("ij")) {
+		t.Fatalf("expect efgh and ij but got %s and %s", first, second)
+	}
+
+	release()
+	if !bytes.Equal(rb.Bytes(), []byte("kl")) {
+		t.Fatalf("expect kl but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	release()
+	if rb.Length() != 2 {
+		t.Fatalf("expect len 2 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	// overwriting resumes once released
+	if _, err := rb.Write([]byte("mnopqrst")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("mnopqrst")) {
+		t.Fatalf("expect mnopqrst but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// release after Reset does not consume new data
+	_, _, release = rb.DrainSegments()
+	rb.Reset()
+	if _, err := rb.Write([]byte("uv")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	release()
+	if !bytes.Equal(rb.Bytes(), []byte("uv")) {
+		t.Fatalf("expect uv but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	// a stale release does not consume the data of a later drain
+	_, _, stale := rb.DrainSegments()
+	stale()
+	if _, err := rb.Write([]byte("wxy")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_, _, release = rb.DrainSegments()
+	stale()
+	if !bytes.Equal(rb.Bytes(), []byte("wxy")) {
+		t.Fatalf("expect wxy but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	release()
+	if rb.Length() != 0 {
+		t.Fatalf("expect len 0 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_DrainSegmentsAllOrNothing(t *testing.T) {
+	rb := New(8, WithOverwrite())
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_, _, release := rb.DrainSegments()
+
+	// writes that must not be split can't evict the held bytes
+	if err := rb.WriteUint32BE(0x01020304); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if n, err := rb.WriteVectored([]byte("xy"), []byte("zw")); !errors.Is(err, ErrFull) || n != 0 {
+		t.Fatalf("expect 0 bytes and ErrFull but got %d, %v", n, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcdef")) {
+		t.Fatalf("expect abcdef but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	release()
+	if err := rb.WriteUint32BE(0x01020304); err != nil {
+		t.Fatalf("WriteUint32BE failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte{1, 2, 3, 4}) {
+		t.Fatalf("expect 01020304 but got %x. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
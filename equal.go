@@ -0,0 +1,43 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "bytes"
+
+// ContentEquals reports whether the unread data of r and other is the same,
+// regardless of where it is stored in each buffer. It compares the data in
+// place, without copying it.
+// Both buffers are locked in a consistent order, as by CopyTo.
+func (r *RingBuffer) ContentEquals(other *RingBuffer) bool {
+	if other == r {
+		return true
+	}
+
+	unlock := lockPair(r, other)
+	defer unlock()
+
+	if r.length() != other.length() {
+		return false
+	}
+	a1, a2 := r.segments()
+	b1, b2 := other.segments()
+	a, b := [][]byte{a1, a2}, [][]byte{b1, b2}
+	for len(a) > 0 && len(b) > 0 {
+		n := len(a[0])
+		if len(b[0]) < n {
+			n = len(b[0])
+		}
+		if !bytes.Equal(a[0][:n], b[0][:n]) {
+			return false
+		}
+		if a[0] = a[0][n:]; len(a[0]) == 0 {
+			a = a[1:]
+		}
+		if b[0] = b[0][n:]; len(b[0]) == 0 {
+			b = b[1:]
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "testing"
+
+func TestRingBuffer_ContentEquals(t *testing.T) {
+	a, b := New(8), New(16)
+	if !a.ContentEquals(b) {
+		t.Fatalf("expect empty buffers to be equal")
+	}
+
+	// a wraps around, b doesn't
+	if _, err := a.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := a.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := a.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := b.Write([]byte("xefghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if a.ContentEquals(b) {
+		t.Fatalf("expect buffers of different lengths to differ")
+	}
+	if _, err := b.ReadByte(); err != nil {
+		t.Fatalf("ReadByte failed: %v", err)
+	}
+	if !a.ContentEquals(b) || !b.ContentEquals(a) {
+		t.Fatalf("expect %s and %s to be equal", a.Bytes(), b.Bytes())
+	}
+	if !a.ContentEquals(a) {
+		t.Fatalf("expect a buffer to equal itself")
+	}
+
+	if err := b.WriteByte('k'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if err := a.WriteByte('l'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if a.ContentEquals(b) {
+		t.Fatalf("expect %s and %s to differ", a.Bytes(), b.Bytes())
+	}
+}
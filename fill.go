@@ -0,0 +1,93 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "io"
+
+// FillFrom reads from src directly into the free space of the buffer,
+// calling src.Read once for each contiguous free segment, which is one
+// segment, or two if the free space wraps around the end of the underlying
+// buffer. It reads into the second segment only if src filled the first.
+// Unlike a loop that reads to io.EOF, it never calls src.Read more than
+// twice, which suits edge-triggered event loops that drain a socket once
+// per readiness event.
+// It returns the number of bytes read and the error from src, if any,
+// ErrFull if the buffer has no free space, or ErrClosed if it is closed.
+// The buffer is locked while reading from src, so src must not call
+// methods on the buffer, and should not block.
+func (r *RingBuffer) FillFrom(src io.Reader) (n int, err error) {
+	r.mu.Lock()
+	if r.closed {
+		err = ErrClosed
+	} else if r.free() == 0 {
+		err = ErrFull
+	}
+	first, second := r.freeSegments()
+	for _, seg := range [2][]byte{first, second} {
+		if err != nil || len(seg) == 0 {
+			break
+		}
+		var m int
+		m, err = src.Read(seg)
+		r.filled(seg[:m])
+		n += m
+		if m < len(seg) {
+			break
+		}
+	}
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
+
+// FreeSegments returns the lengths of the contiguous free segments of the
+// buffer, in the order they are written to: from the write position to the
+// end of the underlying buffer or the read position, and, if the free space
+// wraps around, from the start of the underlying buffer to the read
+// position. secondLen is 0 if the free space doesn't wrap. Like FillFrom,
+// this lets a caller size a vectored read before writing.
+// The result is a snapshot: concurrent reads and writes may change it.
+func (r *RingBuffer) FreeSegments() (firstLen, secondLen int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	first, second := r.freeSegments()
+	return len(first), len(second)
+}
+
+// freeSegments returns the free space of the buffer as one or two slices of
+// the underlying buffer, in the order they are written to. The second slice
+// is nil unless the free space wraps around the end of the underlying
+// buffer. It must be called with r.mu held.
+func (r *RingBuffer) freeSegments() (first, second []byte) {
+	if r.free() == 0 {
+		return nil, nil
+	}
+	if r.w < r.r {
+		return r.buf[r.w:r.r], nil
+	}
+	if r.r == 0 {
+		return r.buf[r.w:], nil
+	}
+	return r.buf[r.w:], r.buf[:r.r]
+}
+
+// filled commits p, which must start at the write position, as written.
+// It must be called with r.mu held.
+func (r *RingBuffer) filled(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	r.updateChecksum(p)
+	r.w = (r.w + len(p)) % r.size
+	if r.w == r.r {
+		r.isFull = true
+	}
+	r.changed()
+	r.signal()
+}
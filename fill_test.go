@@ -0,0 +1,93 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRingBuffer_FillFrom(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	// the free space wraps: 2 bytes at the end and 4 at the start
+	n, err := rb.FillFrom(strings.NewReader("ghijklmnop"))
+	if err != nil {
+		t.Fatalf("FillFrom failed: %v", err)
+	}
+	if n != 6 || !rb.IsFull() {
+		t.Fatalf("expect 6 bytes and a full buffer but got %d. r.w=%d, r.r=%d", n, rb.w, rb.r)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("efghijkl")) {
+		t.Fatalf("expect efghijkl but got %s", rb.Bytes())
+	}
+	if _, err := rb.FillFrom(strings.NewReader("x")); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+
+	// a short read doesn't read into the second segment
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	n, err = rb.FillFrom(iotest.OneByteReader(strings.NewReader("mnop")))
+	if err != nil || n != 1 {
+		t.Fatalf("expect 1 byte but got %d, %v. r.w=%d, r.r=%d", n, err, rb.w, rb.r)
+	}
+
+	// errors from src are returned with the bytes read
+	n, err = rb.FillFrom(iotest.DataErrReader(strings.NewReader("no")))
+	if n != 2 || err != io.EOF {
+		t.Fatalf("expect 2 bytes and io.EOF but got %d, %v", n, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("klmno")) {
+		t.Fatalf("expect klmno but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	_ = rb.Close()
+	if _, err := rb.FillFrom(strings.NewReader("x")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_FreeSegments(t *testing.T) {
+	rb := New(8)
+	if first, second := rb.FreeSegments(); first != 8 || second != 0 {
+		t.Fatalf("expect 8 and 0 but got %d and %d", first, second)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if first, second := rb.FreeSegments(); first != 2 || second != 4 {
+		t.Fatalf("expect 2 and 4 but got %d and %d. r.w=%d, r.r=%d", first, second, rb.w, rb.r)
+	}
+
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if first, second := rb.FreeSegments(); first != 2 || second != 0 {
+		t.Fatalf("expect 2 and 0 but got %d and %d. r.w=%d, r.r=%d", first, second, rb.w, rb.r)
+	}
+
+	if _, err := rb.Write([]byte("kl")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if first, second := rb.FreeSegments(); first != 0 || second != 0 {
+		t.Fatalf("expect 0 and 0 but got %d and %d. r.w=%d, r.r=%d", first, second, rb.w, rb.r)
+	}
+}
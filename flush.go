@@ -0,0 +1,81 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"io"
+)
+
+// Flush writes all unread data to w, in order, consuming it.
+// If w returns an error or writes less than it was given, Flush consumes
+// only the bytes w accepted and returns the error, io.ErrShortWrite for
+// a short write, so a later Flush continues where this one left off.
+// The buffer is locked while writing to w, so w must not call methods on
+// the buffer.
+func (r *RingBuffer) Flush(w io.Writer) error {
+	r.mu.Lock()
+	var err error
+	first, second := r.segments()
+	for _, seg := range [2][]byte{first, second} {
+		if len(seg) == 0 {
+			continue
+		}
+		var n int
+		n, err = w.Write(seg)
+		r.consumed(seg[:n])
+		r.advance(n)
+		if err == nil && n < len(seg) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			break
+		}
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
+
+// ReadToBuffer moves up to max unread bytes into dst, consuming them, with
+// at most one call to dst.Grow and without copying them anywhere else
+// first. It returns the number of bytes moved, and ErrEmpty if the buffer
+// is empty, or ErrClosed if it is also closed.
+func (r *RingBuffer) ReadToBuffer(dst *bytes.Buffer, max int) (n int, err error) {
+	if max <= 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	if l := r.length(); l == 0 {
+		err = r.emptyErr()
+	} else if l < max {
+		max = l
+	}
+	if err == nil {
+		dst.Grow(max)
+		first, second := r.segments()
+		for _, seg := range [2][]byte{first, second} {
+			if len(seg) > max-n {
+				seg = seg[:max-n]
+			}
+			dst.Write(seg)
+			r.consumed(seg)
+			n += len(seg)
+		}
+		r.advance(n)
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
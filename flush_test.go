@@ -0,0 +1,94 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_Flush(t *testing.T) {
+	rb := New(8)
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("xxxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// the sink fails part way through the second segment
+	w := &limitedWriter{n: 3}
+	if err := rb.Flush(w); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("expect io.ErrShortWrite but got %v", err)
+	}
+	if w.String() != "abc" {
+		t.Fatalf("expect abc to be flushed but got %q", w.String())
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("def")) {
+		t.Fatalf("expect def but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// a retry continues where the last flush stopped
+	w.n = 8
+	if err := rb.Flush(w); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if w.String() != "abcdef" {
+		t.Fatalf("expect abcdef to be flushed but got %q", w.String())
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false")
+	}
+
+	if err := rb.Flush(w); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+}
+
+func TestRingBuffer_ReadToBuffer(t *testing.T) {
+	rb := New(8)
+	var dst bytes.Buffer
+
+	if _, err := rb.ReadToBuffer(&dst, 4); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// the unread bytes wrap around
+	n, err := rb.ReadToBuffer(&dst, 5)
+	if err != nil || n != 5 {
+		t.Fatalf("expect 5 bytes but got %d, %v", n, err)
+	}
+	if dst.String() != "efghi" || rb.Length() != 1 {
+		t.Fatalf("expect efghi and 1 byte left but got %s and %d. r.w=%d, r.r=%d", dst.String(), rb.Length(), rb.w, rb.r)
+	}
+	if n, err = rb.ReadToBuffer(&dst, 100); err != nil || n != 1 {
+		t.Fatalf("expect 1 byte but got %d, %v", n, err)
+	}
+	if dst.String() != "efghij" || !rb.IsEmpty() {
+		t.Fatalf("expect efghij and an empty buffer but got %s and %d bytes", dst.String(), rb.Length())
+	}
+
+	_ = rb.Close()
+	if _, err := rb.ReadToBuffer(&dst, 4); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
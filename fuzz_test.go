@@ -0,0 +1,98 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"testing"
+)
+
+// model is a slice-based reference for the behavior of a RingBuffer.
+type model struct {
+	data []byte
+	size int
+}
+
+func (m *model) write(p []byte) int {
+	if n := m.size - len(m.data); len(p) > n {
+		p = p[:n]
+	}
+	m.data = append(m.data, p...)
+	return len(p)
+}
+
+func (m *model) read(n int) []byte {
+	if n > len(m.data) {
+		n = len(m.data)
+	}
+	p := m.data[:n:n]
+	m.data = m.data[n:]
+	return p
+}
+
+// FuzzRingBuffer applies a sequence of operations to a buffer and to a
+// model of it, decoded from pairs of bytes: an operation and its argument.
+// After each operation, the buffer's invariants must hold, and its results
+// and contents must match the model.
+func FuzzRingBuffer(f *testing.F) {
+	f.Add(uint8(8), []byte{0, 6, 1, 4, 0, 6, 2, 'x', 3, 0, 1, 9})
+	f.Add(uint8(3), []byte{2, 'a', 2, 'b', 2, 'c', 2, 'd', 3, 0, 0, 7, 1, 2})
+	f.Add(uint8(1), []byte{0, 2, 3, 0, 3, 0, 2, 'a', 4, 0, 2, 'b'})
+	f.Add(uint8(7), []byte{0, 5, 1, 3, 0, 5, 1, 1, 0, 1, 1, 8, 4, 0, 0, 9})
+	f.Add(uint8(0), []byte{0, 1, 2, 'a', 1, 1, 3, 0})
+
+	f.Fuzz(func(t *testing.T, size uint8, ops []byte) {
+		rb := New(int(size % 32))
+		m := &model{size: rb.Capacity()}
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			arg := ops[i+1]
+			switch ops[i] % 5 {
+			case 0:
+				p := make([]byte, int(arg)%64)
+				for j := range p {
+					p[j] = byte(i + j)
+				}
+				n, _ := rb.Write(p)
+				if want := m.write(p); n != want {
+					t.Fatalf("op %d: expect write %d bytes but got %d", i/2, want, n)
+				}
+			case 1:
+				p := make([]byte, int(arg)%64)
+				n, _ := rb.Read(p)
+				if want := m.read(len(p)); !bytes.Equal(p[:n], want) {
+					t.Fatalf("op %d: expect read %q but got %q", i/2, want, p[:n])
+				}
+			case 2:
+				err := rb.WriteByte(arg)
+				if want := m.write([]byte{arg}); (err == nil) != (want == 1) {
+					t.Fatalf("op %d: expect WriteByte to write %d bytes but got %v", i/2, want, err)
+				}
+			case 3:
+				b, err := rb.ReadByte()
+				want := m.read(1)
+				if (err == nil) != (len(want) == 1) || (err == nil && b != want[0]) {
+					t.Fatalf("op %d: expect ReadByte to return %q but got %q, %v", i/2, want, b, err)
+				}
+			case 4:
+				rb.Reset()
+				m.data = nil
+			}
+
+			rb.mu.Lock()
+			err := rb.checkInvariants()
+			rb.mu.Unlock()
+			if err != nil {
+				t.Fatalf("op %d: %v", i/2, err)
+			}
+			if rb.Length() != len(m.data) {
+				t.Fatalf("op %d: expect len %d bytes but got %d. r.w=%d, r.r=%d", i/2, len(m.data), rb.Length(), rb.w, rb.r)
+			}
+			if got := rb.Bytes(); !bytes.Equal(got, m.data) {
+				t.Fatalf("op %d: expect %q but got %q. r.w=%d, r.r=%d", i/2, m.data, got, rb.w, rb.r)
+			}
+		}
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// NewGrowable returns a new RingBuffer with an initial size that grows
+// on demand up to max bytes.
+// When a write does not fit, the buffer doubles in size until the write fits
+// or max is reached. ErrFull and ErrShortWrite are only returned once the
+// buffer has reached max.
+// If max is less than initial, the buffer does not grow. A max larger than
+// MaxSize is treated as MaxSize.
+func NewGrowable(initial, max int, opts ...Option) *RingBuffer {
+	r := New(initial, opts...)
+	if max > MaxSize {
+		max = MaxSize
+	}
+	r.maxSize = max
+	return r
+}
+
+// WithGrowPolicy replaces the growth strategy of a buffer created by
+// NewGrowable, which by default doubles the size of the buffer until a write
+// fits. When a write doesn't fit, policy is called with the current size and
+// the size needed, and returns the new size and whether to grow at all.
+// The buffer never grows beyond the maximum passed to NewGrowable, and a
+// write that still doesn't fit returns ErrFull or ErrShortWrite as usual.
+// A policy can, for example, grow in fixed increments, or refuse to grow
+// once a memory budget shared by many buffers is spent.
+// policy is called with the buffer locked, so it must not call methods on
+// the buffer.
+func WithGrowPolicy(policy func(current, needed int) (newSize int, ok bool)) Option {
+	return func(r *RingBuffer) {
+		r.growPolicy = policy
+	}
+}
+
+// WithOnGrow sets a callback invoked when a buffer created by NewGrowable
+// grows, with its capacity before and after growing. The callback runs after
+// the buffer's lock is released, so it may call methods on the buffer.
+// Growth by a single write is reported once, from the capacity before the
+// write to the capacity after it. Together with DroppedBytes and Stats, it
+// can reveal a consumer that has stalled while producers keep writing.
+func WithOnGrow(fn func(oldCap, newCap int)) Option {
+	return func(r *RingBuffer) {
+		r.onGrow = fn
+	}
+}
+
+// growHook returns the OnGrow callback, bound to the capacities before and
+// after growing, if the buffer grew since it was last called.
+// It must be called with r.mu held, and the callback invoked after unlocking.
+func (r *RingBuffer) growHook() func() {
+	if !r.grown {
+		return nil
+	}
+	r.grown = false
+	if r.onGrow == nil {
+		return nil
+	}
+	onGrow, oldCap, newCap := r.onGrow, r.grewFrom, r.size
+	return func() { onGrow(oldCap, newCap) }
+}
+
+// canGrow reports whether the buffer would grow to hold n bytes.
+// It must be called with r.mu held.
+func (r *RingBuffer) canGrow(n int) bool {
+	if n > r.maxSize {
+		return false
+	}
+	if r.growPolicy == nil {
+		return true
+	}
+	size, ok := r.growPolicy(r.size, n)
+	return ok && size >= n
+}
+
+// grow grows the buffer so that it can hold n bytes, without exceeding
+// maxSize, by doubling its size unless there is a grow policy.
+// It must be called with r.mu held.
+func (r *RingBuffer) grow(n int) {
+	var size int
+	if r.growPolicy != nil {
+		var ok bool
+		if size, ok = r.growPolicy(r.size, n); !ok {
+			return
+		}
+	} else {
+		size = r.size
+		if size == 0 {
+			size = 1
+		}
+		for size < n && size < r.maxSize {
+			size *= 2
+		}
+	}
+	if size > r.maxSize {
+		size = r.maxSize
+	}
+	if size <= r.size {
+		return
+	}
+	if !r.grown {
+		r.grown = true
+		r.grewFrom = r.size
+	}
+	r.resize(size)
+}
+
+// Shrink reallocates the buffer with a smaller size, the larger of min and
+// the number of unread bytes, so that a buffer that grew to handle a burst
+// of data can release the memory. It moves the unread bytes to the start of
+// the new buffer. It does nothing if the buffer is already no larger than
+// that. A buffer created by NewGrowable can grow again afterwards, up to its
+// maximum.
+func (r *RingBuffer) Shrink(min int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	size := r.length()
+	if min > size {
+		size = min
+	}
+	if size < r.size {
+		r.resize(size)
+		r.changed()
+	}
+}
+
+// resize replaces the underlying buffer with one of the given size,
+// moving the unread bytes to its start. The unread bytes must fit.
+// It must be called with r.mu held.
+func (r *RingBuffer) resize(size int) {
+	buf := makeBuf(size)
+	n := r.peek(buf)
+
+	r.buf = buf
+	r.size = size
+	r.r = 0
+	r.replay = 0
+	r.w = n
+	if r.w == r.size {
+		r.w = 0
+	}
+	r.isFull = n > 0 && n == r.size
+}
@@ -0,0 +1,337 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer_Growable(t *testing.T) {
+	rb := NewGrowable(4, 16)
+
+	// wrap the buffer before growing
+	if _, err := rb.Write([]byte("abc")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 2)
+	if _, err := rb.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("def")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.Capacity() != 4 {
+		t.Fatalf("expect capacity 4 but got %d", rb.Capacity())
+	}
+
+	// grow from 4 to 8
+	n, err := rb.Write([]byte("gh"))
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expect write 2 bytes but got %d", n)
+	}
+	if rb.Capacity() != 8 {
+		t.Fatalf("expect capacity 8 but got %d", rb.Capacity())
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cdefgh")) {
+		t.Fatalf("expect cdefgh but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// grow from 8 to 16 in one write
+	if _, err := rb.Write([]byte(strings.Repeat("x", 10))); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.Capacity() != 16 {
+		t.Fatalf("expect capacity 16 but got %d", rb.Capacity())
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false")
+	}
+
+	// max reached
+	n, err = rb.Write([]byte("y"))
+	if !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect write 0 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cdefgh"+strings.Repeat("x", 10))) {
+		t.Fatalf("expect cdefgh and 10 x but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_GrowableMax(t *testing.T) {
+	rb := NewGrowable(4, 6)
+
+	n, err := rb.Write([]byte(strings.Repeat("a", 10)))
+	if !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+	if n != 6 {
+		t.Fatalf("expect write 6 bytes but got %d", n)
+	}
+	if rb.Capacity() != 6 {
+		t.Fatalf("expect capacity 6 but got %d", rb.Capacity())
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_GrowableWriteByte(t *testing.T) {
+	rb := NewGrowable(0, 2)
+
+	for _, c := range []byte("ab") {
+		if err := rb.WriteByte(c); err != nil {
+			t.Fatalf("WriteByte failed: %v", err)
+		}
+	}
+	if rb.Capacity() != 2 {
+		t.Fatalf("expect capacity 2 but got %d", rb.Capacity())
+	}
+	if err := rb.WriteByte('c'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("ab")) {
+		t.Fatalf("expect ab but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_Shrink(t *testing.T) {
+	rb := NewGrowable(4, 64)
+
+	if _, err := rb.Write([]byte(strings.Repeat("a", 30))); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.Capacity() != 32 {
+		t.Fatalf("expect capacity 32 but got %d", rb.Capacity())
+	}
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Read(make([]byte, 28)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("bcde")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// never below the unread data
+	rb.Shrink(2)
+	if rb.Capacity() != 6 {
+		t.Fatalf("expect capacity 6 but got %d", rb.Capacity())
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("aabcde")) {
+		t.Fatalf("expect aabcde but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false")
+	}
+
+	// no-op when already small enough
+	rb.Shrink(16)
+	if rb.Capacity() != 6 {
+		t.Fatalf("expect capacity 6 but got %d", rb.Capacity())
+	}
+
+	// and it grows again
+	if _, err := rb.Write([]byte("fg")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("aabcdefg")) {
+		t.Fatalf("expect aabcdefg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	if _, err := rb.Read(make([]byte, 8)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	rb.Shrink(0)
+	if rb.Capacity() != 0 {
+		t.Fatalf("expect capacity 0 but got %d", rb.Capacity())
+	}
+}
+
+func TestRingBuffer_GrowPolicy(t *testing.T) {
+	budget := 8
+	var calls int
+	rb := NewGrowable(4, 64, WithGrowPolicy(func(current, needed int) (int, bool) {
+		calls++
+		// grow in steps of 4 bytes within the budget
+		size := (needed + 3) / 4 * 4
+		if size > budget {
+			return 0, false
+		}
+		return size, true
+	}))
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.Capacity() != 8 {
+		t.Fatalf("expect capacity 8 but got %d", rb.Capacity())
+	}
+	if calls != 1 {
+		t.Fatalf("expect 1 call but got %d", calls)
+	}
+
+	// refused: a short write
+	n, err := rb.Write([]byte("ghijklmn"))
+	if !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+	if n != 2 || rb.Capacity() != 8 {
+		t.Fatalf("expect write 2 bytes with capacity 8 but got %d with %d", n, rb.Capacity())
+	}
+	if err := rb.WriteByte('o'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+
+	budget = 64
+	if err := rb.WriteByte('o'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if rb.Capacity() != 12 {
+		t.Fatalf("expect capacity 12 but got %d", rb.Capacity())
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcdefgho")) {
+		t.Fatalf("expect abcdefgho but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_GrowPolicyBlocking(t *testing.T) {
+	rb := NewGrowable(4, 64, WithGrowPolicy(func(current, needed int) (int, bool) {
+		return 0, false
+	}))
+
+	// a blocked write waits for a reader rather than for growth
+	done := make(chan error)
+	go func() {
+		_, err := rb.BlockingWrite([]byte("abcdef"))
+		done <- err
+	}()
+	buf := make([]byte, 6)
+	n := 0
+	for n < 6 {
+		m, _ := rb.BlockingRead(buf[n:])
+		n += m
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("BlockingWrite failed: %v", err)
+	}
+	if !bytes.Equal(buf, []byte("abcdef")) {
+		t.Fatalf("expect abcdef but got %s", buf)
+	}
+}
+
+func TestRingBuffer_OnGrow(t *testing.T) {
+	var rb *RingBuffer
+	var grew [][2]int
+	var full int
+	rb = NewGrowable(2, 16,
+		WithOnGrow(func(oldCap, newCap int) {
+			grew = append(grew, [2]int{oldCap, newCap})
+			// callbacks run outside the lock
+			_ = rb.Length()
+		}),
+		WithOnFull(func() { full++ }),
+	)
+
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if len(grew) != 0 || full != 1 {
+		t.Fatalf("expect no growth and 1 OnFull call but got %v and %d", grew, full)
+	}
+	// one write that doubles twice is reported once
+	if _, err := rb.Write([]byte("cdefg")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if len(grew) != 1 || grew[0] != [2]int{2, 8} {
+		t.Fatalf("expect growth from 2 to 8 but got %v", grew)
+	}
+	if err := rb.WriteByte('h'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if len(grew) != 1 {
+		t.Fatalf("expect no more growth but got %v", grew)
+	}
+	if err := rb.WriteByte('i'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if len(grew) != 2 || grew[1] != [2]int{8, 16} {
+		t.Fatalf("expect growth from 8 to 16 but got %v", grew)
+	}
+
+	// shrinking is not growth
+	rb.Shrink(0)
+	if _, err := rb.Write([]byte("j")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if len(grew) != 3 || grew[2] != [2]int{9, 16} {
+		t.Fatalf("expect growth from 9 to 16 but got %v", grew)
+	}
+}
+
+func TestRingBuffer_GrowPolicyPartialRefusal(t *testing.T) {
+	// the policy allows growth to 10 bytes but no further
+	rb := NewGrowable(8, 64, WithGrowPolicy(func(current, needed int) (int, bool) {
+		return needed, needed <= 10
+	}))
+	if _, err := rb.Write([]byte("abcdefgh")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// the blocked write must wait for a reader rather than spin
+	done := make(chan error)
+	go func() {
+		_, err := rb.BlockingWrite([]byte("ijklm"))
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	buf := make([]byte, 13)
+	n := 0
+	for n < 13 {
+		m, err := rb.ReadTimeout(buf[n:], time.Second)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		n += m
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("BlockingWrite failed: %v", err)
+	}
+	if !bytes.Equal(buf, []byte("abcdefghijklm")) {
+		t.Fatalf("expect abcdefghijklm but got %s", buf)
+	}
+}
+
+func TestRingBuffer_GrowableClosed(t *testing.T) {
+	var grew int
+	rb := NewGrowable(4, 128, WithOnGrow(func(oldCap, newCap int) { grew++ }))
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = rb.Close()
+
+	// writes to a closed buffer fail without growing it
+	if _, err := rb.Write(make([]byte, 100)); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+	if err := rb.WriteByte('e'); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+	if err := rb.WriteByteFront('e'); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+	if rb.Capacity() != 4 || grew != 0 {
+		t.Fatalf("expect capacity 4 and no growth but got %d and %d calls", rb.Capacity(), grew)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "fmt"
+
+// checkInvariants returns an error describing the first inconsistency found
+// in the buffer's positions, or nil if there is none.
+// Builds with the debug tag check the invariants after every change to the
+// buffer, and panic if one fails. It must be called with r.mu held.
+func (r *RingBuffer) checkInvariants() error {
+	if r.size != len(r.buf) {
+		return fmt.Errorf("ringbuffer: size %d but buffer of %d bytes", r.size, len(r.buf))
+	}
+	if r.r < 0 || r.w < 0 || (r.size > 0 && (r.r >= r.size || r.w >= r.size)) ||
+		(r.size == 0 && (r.r != 0 || r.w != 0)) {
+		return fmt.Errorf("ringbuffer: positions out of range. r.w=%d, r.r=%d, size=%d", r.w, r.r, r.size)
+	}
+	if r.isFull && r.r != r.w {
+		return fmt.Errorf("ringbuffer: full with r.w=%d, r.r=%d", r.w, r.r)
+	}
+	if r.isFull && r.size == 0 {
+		return fmt.Errorf("ringbuffer: full with size 0")
+	}
+	if l, f := r.length(), r.free(); l+f != r.size {
+		return fmt.Errorf("ringbuffer: length %d and free %d don't add up to size %d", l, f, r.size)
+	}
+	if r.replay < 0 || r.replay > r.free() {
+		return fmt.Errorf("ringbuffer: %d replayable bytes but %d free", r.replay, r.free())
+	}
+	return nil
+}
+
+// DebugState returns a consistent snapshot of the buffer's internal
+// positions: the read index r, the write index w, the size of the underlying
+// buffer, and whether the buffer is full, which tells an empty buffer from a
+// full one when r == w. It is meant for diagnostics, such as attaching to bug
+// reports, and the values have no meaning to the rest of the API.
+func (r *RingBuffer) DebugState() (rpos, wpos, size int, isFull bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r, r.w, r.size, r.isFull
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "testing"
+
+func TestRingBuffer_CheckInvariants(t *testing.T) {
+	rb := New(4)
+	if err := rb.checkInvariants(); err != nil {
+		t.Fatalf("expect no error but got %v", err)
+	}
+
+	rb.r, rb.w, rb.isFull = 1, 2, true
+	if err := rb.checkInvariants(); err == nil {
+		t.Fatalf("expect an error for a full buffer with r != w")
+	}
+	rb.r, rb.w, rb.isFull = 4, 0, false
+	if err := rb.checkInvariants(); err == nil {
+		t.Fatalf("expect an error for r out of range")
+	}
+	rb.r, rb.replay = 0, 5
+	if err := rb.checkInvariants(); err == nil {
+		t.Fatalf("expect an error for too many replayable bytes")
+	}
+}
+
+func TestRingBuffer_DebugState(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	r, w, size, isFull := rb.DebugState()
+	if r != 4 || w != 2 || size != 8 || isFull {
+		t.Fatalf("expect 4, 2, 8, false but got %d, %d, %d, %v", r, w, size, isFull)
+	}
+
+	if _, err := rb.Write([]byte("kl")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if r, w, _, isFull = rb.DebugState(); r != 4 || w != 4 || !isFull {
+		t.Fatalf("expect a full buffer at 4 but got r.w=%d, r.r=%d, full=%v", w, r, isFull)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package ringbuffer
+
+import "iter"
+
+// All returns an iterator over the unread bytes and their offsets from the
+// read position, oldest first. It does not consume the bytes or copy them.
+// The buffer is locked for each step rather than for the whole loop, so the
+// loop body may call methods on the buffer, but the sequence is undefined
+// if the buffer is modified during iteration.
+func (r *RingBuffer) All() iter.Seq2[int, byte] {
+	return func(yield func(int, byte) bool) {
+		for i := 0; ; i++ {
+			r.mu.Lock()
+			if i >= r.length() {
+				r.mu.Unlock()
+				return
+			}
+			b := r.buf[(r.r+i)%r.size]
+			r.mu.Unlock()
+
+			if !yield(i, b) {
+				return
+			}
+		}
+	}
+}
+
+// Consume returns an iterator that reads the unread bytes one at a time,
+// oldest first, as ReadByte does, and stops once the buffer is empty.
+// Each byte is consumed before it is yielded, so breaking out of the loop
+// leaves the remaining bytes in the buffer. The buffer is locked for each
+// step, so the loop body may call methods on the buffer, including writes
+// that extend the iteration.
+func (r *RingBuffer) Consume() iter.Seq[byte] {
+	return func(yield func(byte) bool) {
+		for {
+			b, err := r.ReadByte()
+			if err != nil || !yield(b) {
+				return
+			}
+		}
+	}
+}
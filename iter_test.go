@@ -0,0 +1,95 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package ringbuffer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRingBuffer_All(t *testing.T) {
+	rb := New(8)
+	for range rb.All() {
+		t.Fatalf("expect no bytes from an empty buffer")
+	}
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghijkl")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	var got []byte
+	for i, b := range rb.All() {
+		if i != len(got) {
+			t.Fatalf("expect index %d but got %d", len(got), i)
+		}
+		got = append(got, b)
+	}
+	if !bytes.Equal(got, []byte("efghijkl")) {
+		t.Fatalf("expect efghijkl but got %s. r.w=%d, r.r=%d", got, rb.w, rb.r)
+	}
+	if rb.Length() != 8 {
+		t.Fatalf("expect len 8 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	got = got[:0]
+	for _, b := range rb.All() {
+		if b == 'h' {
+			break
+		}
+		got = append(got, b)
+	}
+	if !bytes.Equal(got, []byte("efg")) {
+		t.Fatalf("expect efg but got %s", got)
+	}
+}
+
+func TestRingBuffer_Consume(t *testing.T) {
+	rb := New(8)
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghijkl")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	var got []byte
+	for b := range rb.Consume() {
+		got = append(got, b)
+		if b == 'h' {
+			break
+		}
+	}
+	if !bytes.Equal(got, []byte("efgh")) {
+		t.Fatalf("expect efgh but got %s", got)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("ijkl")) {
+		t.Fatalf("expect ijkl but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	got = got[:0]
+	for b := range rb.Consume() {
+		got = append(got, b)
+	}
+	if !bytes.Equal(got, []byte("ijkl")) {
+		t.Fatalf("expect ijkl but got %s", got)
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+}
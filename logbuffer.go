@@ -0,0 +1,79 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// logHeaderLen is the size of the header of a LogBuffer entry: the length of
+// the message as a 4-byte big-endian integer, followed by the time it was
+// appended in nanoseconds since the Unix epoch as an 8-byte big-endian integer.
+const logHeaderLen = 4 + 8
+
+// LogEntry is a message appended to a LogBuffer.
+type LogEntry struct {
+	Time time.Time
+	Msg  []byte
+}
+
+// LogBuffer is an in-memory log that keeps the most recent messages that fit
+// in a fixed amount of memory, evicting whole messages, oldest first, to
+// make room for new ones. Each message takes 12 bytes more than its length.
+// It is safe for concurrent use by multiple goroutines.
+type LogBuffer struct {
+	rb *RingBuffer
+}
+
+// NewLogBuffer returns a new LogBuffer that uses size bytes of memory.
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{rb: New(size)}
+}
+
+// Append adds msg to the log, timestamped with the current time, evicting
+// the oldest messages as needed. It returns ErrTooLarge if msg can't fit
+// even in an empty log.
+func (b *LogBuffer) Append(msg []byte) error {
+	total := logHeaderLen + len(msg)
+	if uint64(len(msg)) > math.MaxUint32 || total > b.rb.Capacity() {
+		return ErrTooLarge
+	}
+	var header [logHeaderLen]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(msg)))
+	binary.BigEndian.PutUint64(header[4:], uint64(time.Now().UnixNano()))
+
+	r := b.rb
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.free() < total {
+		var prefix [4]byte
+		r.peek(prefix[:])
+		r.advance(logHeaderLen + int(binary.BigEndian.Uint32(prefix[:])))
+	}
+	return r.writeVectored([][]byte{header[:], msg}, total)
+}
+
+// Dump returns the messages in the log, oldest first, without removing them.
+func (b *LogBuffer) Dump() []LogEntry {
+	b.rb.mu.Lock()
+	p := b.rb.bytes()
+	b.rb.mu.Unlock()
+
+	var entries []LogEntry
+	for len(p) >= logHeaderLen {
+		n := int(binary.BigEndian.Uint32(p[:4]))
+		t := int64(binary.BigEndian.Uint64(p[4:logHeaderLen]))
+		p = p[logHeaderLen:]
+		entries = append(entries, LogEntry{
+			Time: time.Unix(0, t),
+			Msg:  p[:n:n],
+		})
+		p = p[n:]
+	}
+	return entries
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLogBuffer(t *testing.T) {
+	// room for three 4-byte messages and some slack
+	b := NewLogBuffer(3*(logHeaderLen+4) + 5)
+
+	if entries := b.Dump(); len(entries) != 0 {
+		t.Fatalf("expect no entries but got %d", len(entries))
+	}
+
+	start := time.Now()
+	for _, msg := range []string{"one.", "two.", "thr."} {
+		if err := b.Append([]byte(msg)); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+	entries := b.Dump()
+	if len(entries) != 3 || string(entries[0].Msg) != "one." || string(entries[2].Msg) != "thr." {
+		t.Fatalf("expect one., two., thr. but got %q", entries)
+	}
+	if entries[0].Time.Before(start.Truncate(time.Nanosecond)) || entries[2].Time.Before(entries[0].Time) {
+		t.Fatalf("expect ordered timestamps after %v but got %v and %v", start, entries[0].Time, entries[2].Time)
+	}
+
+	// evicts "one." and "two." as a whole, wrapping around the end
+	if err := b.Append([]byte("four and five")); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	entries = b.Dump()
+	if len(entries) != 2 || string(entries[0].Msg) != "thr." || string(entries[1].Msg) != "four and five" {
+		t.Fatalf("expect thr., four and five but got %q", entries)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := b.Append([]byte("abcd")); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+	entries = b.Dump()
+	if len(entries) != 3 {
+		t.Fatalf("expect 3 entries but got %d", len(entries))
+	}
+	for _, e := range entries {
+		if string(e.Msg) != "abcd" {
+			t.Fatalf("expect abcd but got %q", e.Msg)
+		}
+	}
+
+	if err := b.Append(make([]byte, 3*(logHeaderLen+4)+5-logHeaderLen+1)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expect ErrTooLarge but got %v", err)
+	}
+}
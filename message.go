@@ -0,0 +1,54 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// WriteMessage writes p to the buffer as a single message.
+// Either all of p is written, or nothing is written and ErrFull returned,
+// so messages from concurrent producers are never interleaved.
+// Message boundaries are kept separately from the data, so messages have no
+// framing overhead in the buffer. They are only preserved if the buffer is
+// read with ReadMessage; mixing in other reading methods breaks them.
+func (r *RingBuffer) WriteMessage(p []byte) error {
+	r.mu.Lock()
+	err := r.writeAll(p)
+	if err == nil {
+		r.msgs = append(r.msgs, len(p))
+	}
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
+
+// ReadMessage reads the oldest message written by WriteMessage.
+// It returns ErrEmpty if there are no messages, or ErrClosed if the buffer
+// is closed and there are no messages left.
+func (r *RingBuffer) ReadMessage() ([]byte, error) {
+	r.mu.Lock()
+	p, err := r.readMessage()
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return p, err
+}
+
+// readMessage is ReadMessage without locking. It must be called with r.mu held.
+func (r *RingBuffer) readMessage() ([]byte, error) {
+	if len(r.msgs) == 0 {
+		return nil, r.emptyErr()
+	}
+	n := r.msgs[0]
+	r.msgs = r.msgs[1:]
+
+	p := make([]byte, n)
+	_, _ = r.read(p)
+	return p, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestRingBuffer_Message(t *testing.T) {
+	rb := New(8)
+
+	if _, err := rb.ReadMessage(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	for _, p := range []string{"abc", "", "de"} {
+		if err := rb.WriteMessage([]byte(p)); err != nil {
+			t.Fatalf("WriteMessage failed: %v", err)
+		}
+	}
+	if err := rb.WriteMessage([]byte("fghi")); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	for _, want := range []string{"abc", "", "de"} {
+		p, err := rb.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage failed: %v", err)
+		}
+		if string(p) != want {
+			t.Fatalf("expect %q but got %q", want, p)
+		}
+	}
+	if _, err := rb.ReadMessage(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+}
+
+func TestRingBuffer_MessageConcurrent(t *testing.T) {
+	rb := New(64)
+	msgs := [][]byte{
+		bytes.Repeat([]byte("a"), 7),
+		bytes.Repeat([]byte("b"), 11),
+		bytes.Repeat([]byte("c"), 13),
+	}
+
+	var wg sync.WaitGroup
+	for _, msg := range msgs {
+		wg.Add(1)
+		go func(msg []byte) {
+			defer wg.Done()
+			for i := 0; i < 100; {
+				if err := rb.WriteMessage(msg); err == nil {
+					i++
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}(msg)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var count int
+	for count < 300 {
+		p, err := rb.ReadMessage()
+		if errors.Is(err, ErrEmpty) {
+			runtime.Gosched()
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ReadMessage failed: %v", err)
+		}
+		if len(p) == 0 || !bytes.Equal(p, bytes.Repeat(p[:1], len(p))) {
+			t.Fatalf("expect a single producer's message but got %q", p)
+		}
+		count++
+	}
+	<-done
+}
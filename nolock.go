@@ -0,0 +1,39 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "sync"
+
+// WithNoLock disables locking, which saves the cost of locking and
+// unlocking a mutex in every method when the buffer is only ever used by
+// one goroutine at a time.
+//
+// A buffer created with WithNoLock is NOT safe for concurrent use.
+// Concurrent calls corrupt the buffer silently. Blocking methods, such as
+// BlockingRead, and hooks that run in another goroutine must not be used
+// either, since nothing else can change the buffer while a method blocks.
+func WithNoLock() Option {
+	return func(r *RingBuffer) {
+		r.mu.disabled = true
+	}
+}
+
+// locker is a mutex that can be disabled.
+type locker struct {
+	mu       sync.Mutex
+	disabled bool
+}
+
+func (l *locker) Lock() {
+	if !l.disabled {
+		l.mu.Lock()
+	}
+}
+
+func (l *locker) Unlock() {
+	if !l.disabled {
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRingBuffer_NoLock(t *testing.T) {
+	rb := New(4, WithNoLock())
+
+	if _, err := rb.Write([]byte("abc")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if b, err := rb.ReadByte(); err != nil || b != 'a' {
+		t.Fatalf("expect a but got %c, %v", b, err)
+	}
+	if _, err := rb.Write([]byte("de")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("bcde")) {
+		t.Fatalf("expect bcde but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false")
+	}
+}
+
+func BenchmarkRingBuffer_NoLock(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"Mutex", nil},
+		{"NoLock", []Option{WithNoLock()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			rb := New(1024, bc.opts...)
+			for i := 0; i < b.N; i++ {
+				_ = rb.WriteByte('a')
+				_, _ = rb.ReadByte()
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// NotifyChan returns a channel that receives a value after data is written
+// to the buffer, for use in select-based event loops.
+// The channel is edge-triggered and coalescing: it holds at most one pending
+// notification, however many writes happen before it is received, and
+// writers never block on it. So a receiver should read all available data
+// after each notification, rather than assuming one notification per write.
+// Every call returns the same channel.
+func (r *RingBuffer) NotifyChan() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.notify == nil {
+		r.notify = make(chan struct{}, 1)
+	}
+	return r.notify
+}
+
+// signal sends a notification on the notify channel, if any,
+// without blocking. It must be called with r.mu held.
+func (r *RingBuffer) signal() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRingBuffer_NotifyChan(t *testing.T) {
+	rb := New(8)
+
+	// writes without a channel don't block
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	ch := rb.NotifyChan()
+	if ch != rb.NotifyChan() {
+		t.Fatalf("expect the same channel from every call")
+	}
+	select {
+	case <-ch:
+		t.Fatalf("expect no notification before a write")
+	default:
+	}
+
+	// notifications are coalesced
+	if _, err := rb.Write([]byte("cd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := rb.WriteByte('e'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatalf("expect a notification after a write")
+	}
+	select {
+	case <-ch:
+		t.Fatalf("expect a single notification for several writes")
+	default:
+	}
+
+	// failed writes don't notify
+	if _, err := rb.Write([]byte("fghi")); err == nil {
+		t.Fatalf("expect ErrShortWrite but got nil")
+	}
+	<-ch
+	if _, err := rb.Write([]byte("j")); err == nil {
+		t.Fatalf("expect ErrFull but got nil")
+	}
+	select {
+	case <-ch:
+		t.Fatalf("expect no notification for a failed write")
+	default:
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// Option configures a RingBuffer created by New.
+type Option func(*RingBuffer)
+
+// WithStrictWriter makes Write atomic: it either writes all of p or writes
+// nothing and returns ErrFull. By default, Write writes as much of p as fits
+// and returns ErrShortWrite, which makes the most of the free space but can
+// split data that should be written as a unit.
+// Strict writes that are larger than the buffer can never succeed, so
+// callers such as io.Copy should use Writer or a buffer at least as large
+// as their writes.
+func WithStrictWriter() Option {
+	return func(r *RingBuffer) {
+		r.strict = true
+	}
+}
+
+// WithEOFOnEmpty makes Read, ReadByte and other methods that find the
+// buffer empty return io.EOF instead of ErrEmpty, for code that expects
+// the errors of an io.Reader. Unlike the error returned once the buffer is
+// closed, which is still ErrClosed, the io.EOF is transient: more data may
+// be written later. Since io.EOF normally means the end of a stream,
+// helpers such as io.Copy and io.ReadAll stop at the first empty read
+// without reporting an error, and callers can no longer tell an empty
+// buffer from the end of their data. Methods that return ErrEmpty because
+// only part of a value, line or record is buffered are not affected.
+func WithEOFOnEmpty() Option {
+	return func(r *RingBuffer) {
+		r.eofOnEmpty = true
+	}
+}
+
+// WithSpinWait makes blocking reads and writes, such as BlockingRead,
+// yield the processor up to iterations times, re-checking the buffer each
+// time, before parking the goroutine until the buffer changes.
+// Spinning reduces wakeup latency when the other side is expected to
+// respond quickly, at the cost of CPU time burned while spinning.
+// Non-blocking methods are not affected.
+func WithSpinWait(iterations int) Option {
+	return func(r *RingBuffer) {
+		r.spins = iterations
+	}
+}
+
+// WithMaxReadChunk limits each call to Read, BlockingRead, ReadContext and
+// ReadTimeout to copying at most n bytes, even if p has room for more and
+// more is buffered, so that a goroutine serving many buffers can interleave
+// them without one large read monopolizing it. ReadBetween reads at least
+// its min bytes regardless. The limit only caps a single call, so helpers
+// that loop until p is full, such as io.ReadFull, are not affected other
+// than by making more calls. Methods that read a whole value, record or
+// message at once are not limited. A zero or negative n means no limit.
+func WithMaxReadChunk(n int) Option {
+	return func(r *RingBuffer) {
+		r.maxReadChunk = n
+	}
+}
+
+// WithOnFull sets a callback invoked when a write fills the buffer.
+// The callback runs after the buffer's lock is released, so it may call
+// methods on the buffer. It is not invoked again until the buffer has
+// been empty, whether drained by a read or emptied by Reset.
+func WithOnFull(fn func()) Option {
+	return func(r *RingBuffer) {
+		r.onFull = fn
+	}
+}
+
+// WithOnEmpty sets a callback invoked when a read drains the buffer.
+// The callback runs after the buffer's lock is released, so it may call
+// methods on the buffer. It is not invoked again until data has been
+// written to the buffer. Emptying the buffer with Reset does not invoke it.
+func WithOnEmpty(fn func()) Option {
+	return func(r *RingBuffer) {
+		r.onEmpty = fn
+	}
+}
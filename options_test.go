@@ -0,0 +1,258 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_OnFullOnEmpty(t *testing.T) {
+	var rb *RingBuffer
+	var full, empty int
+	rb = New(4,
+		WithOnFull(func() {
+			full++
+			// callbacks run outside the lock
+			_ = rb.Length()
+		}),
+		WithOnEmpty(func() {
+			empty++
+			_ = rb.Free()
+		}),
+	)
+
+	// the first drain is reported even if the buffer was never full
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 2)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if empty != 1 {
+		t.Fatalf("expect 1 OnEmpty call but got %d", empty)
+	}
+
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if full != 0 {
+		t.Fatalf("expect OnFull not called but got %d calls", full)
+	}
+	if _, err := rb.Write([]byte("cd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if full != 1 {
+		t.Fatalf("expect 1 OnFull call but got %d", full)
+	}
+
+	// refilling without draining is debounced
+	if _, err := rb.ReadByte(); err != nil {
+		t.Fatalf("ReadByte failed: %v", err)
+	}
+	if err := rb.WriteByte('e'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if full != 1 {
+		t.Fatalf("expect 1 OnFull call but got %d", full)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := rb.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if empty != 2 {
+		t.Fatalf("expect 2 OnEmpty call but got %d", empty)
+	}
+
+	// a failed read on an empty buffer is not a transition
+	if _, err := rb.Read(buf); err == nil {
+		t.Fatalf("expect ErrEmpty but got nil")
+	}
+	if empty != 2 {
+		t.Fatalf("expect 2 OnEmpty call but got %d", empty)
+	}
+
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if full != 2 {
+		t.Fatalf("expect 2 OnFull calls but got %d", full)
+	}
+	// draining without ever filling is a transition to empty
+	rb.Reset()
+	if _, err := rb.Write([]byte("abc")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if empty != 3 {
+		t.Fatalf("expect 3 OnEmpty calls but got %d", empty)
+	}
+}
+
+func TestRingBuffer_OnEmptyReset(t *testing.T) {
+	var empty int
+	rb := New(8, WithOnEmpty(func() { empty++ }))
+
+	// discarding data is not a read that drains the buffer
+	if _, err := rb.Write([]byte("abc")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	rb.Reset()
+	if _, err := rb.Read(make([]byte, 3)); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if empty != 0 {
+		t.Fatalf("expect no OnEmpty calls but got %d", empty)
+	}
+}
+
+func TestRingBuffer_StrictWriter(t *testing.T) {
+	rb := New(8, WithStrictWriter())
+
+	n, err := rb.Write([]byte("abcde"))
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("expect write 5 bytes but got %d", n)
+	}
+
+	n, err = rb.WriteString("fghi")
+	if !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect write 0 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcde")) {
+		t.Fatalf("expect abcde but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	n, err = rb.Write([]byte("fgh"))
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expect write 3 bytes but got %d", n)
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false")
+	}
+}
+
+func TestRingBuffer_MaxReadChunk(t *testing.T) {
+	rb := New(8, WithMaxReadChunk(3))
+	if _, err := rb.Write([]byte("abcdefgh")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	buf := make([]byte, 8)
+	n, err := rb.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !bytes.Equal(buf[:n], []byte("abc")) {
+		t.Fatalf("expect abc but got %s. r.w=%d, r.r=%d", buf[:n], rb.w, rb.r)
+	}
+	if n, _ = rb.BlockingRead(buf); !bytes.Equal(buf[:n], []byte("def")) {
+		t.Fatalf("expect def but got %s. r.w=%d, r.r=%d", buf[:n], rb.w, rb.r)
+	}
+
+	// ReadBetween reads at least min bytes
+	if _, err := rb.Write([]byte("ijklmn")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n, _ = rb.ReadBetween(buf, 5); !bytes.Equal(buf[:n], []byte("ghijk")) {
+		t.Fatalf("expect ghijk but got %s. r.w=%d, r.r=%d", buf[:n], rb.w, rb.r)
+	}
+
+	// io.ReadFull loops past the limit
+	if _, err := io.ReadFull(rb, buf[:3]); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("abcdefgh")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := io.ReadFull(rb, buf); err != nil {
+		t.Fatalf("ReadFull failed: %v", err)
+	}
+	if !bytes.Equal(buf, []byte("abcdefgh")) {
+		t.Fatalf("expect abcdefgh but got %s", buf)
+	}
+}
+
+func TestRingBuffer_EOFOnEmpty(t *testing.T) {
+	rb := New(8, WithEOFOnEmpty())
+
+	if _, err := rb.Read(make([]byte, 4)); err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+	if _, err := rb.ReadByte(); err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+
+	// io.EOF is transient
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	p, err := io.ReadAll(rb)
+	if err != nil || !bytes.Equal(p, []byte("ab")) {
+		t.Fatalf("expect ab but got %q, %v", p, err)
+	}
+
+	_ = rb.Close()
+	if _, err := rb.Read(make([]byte, 4)); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+
+	if _, err := New(8).Read(make([]byte, 4)); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty by default but got %v", err)
+	}
+}
+
+func TestRingBuffer_EOFOnEmptyPartial(t *testing.T) {
+	rb := New(16, WithEOFOnEmpty())
+
+	// an empty buffer returns io.EOF from every kind of read
+	if _, err := rb.ReadUint32BE(); err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+	if _, err := rb.ReadRecord(); err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+	if _, err := rb.ReadBytes('\n'); err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+
+	// part of a value is ErrEmpty: more of it is expected
+	if _, err := rb.Write([]byte{0}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.ReadUint32BE(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.ReadRecord(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.ReadBytes('\n'); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	rbuf := &RecordBuffer{rb: New(8, WithEOFOnEmpty()), recordSize: 4}
+	if _, err := rbuf.Pop(); err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+	if _, err := rbuf.rb.Write([]byte{0}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rbuf.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+}
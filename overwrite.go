@@ -0,0 +1,46 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// WithOverwrite makes writes that don't fit discard the oldest unread bytes
+// to make room, instead of returning ErrFull or ErrShortWrite, so that the
+// buffer always holds the most recently written data. A write larger than
+// the buffer keeps only its last Capacity() bytes.
+// The number of bytes discarded is reported by DroppedBytes.
+// Overwriting discards bytes without regard to message or record
+// boundaries, so it should not be combined with WriteMessage or WriteRecord.
+// While segments returned by DrainSegments are held, writes don't overwrite
+// and return ErrFull or ErrShortWrite as usual.
+func WithOverwrite() Option {
+	return func(r *RingBuffer) {
+		r.overwrite = true
+	}
+}
+
+// evict discards the n oldest unread bytes to make room for a write.
+// It must be called with r.mu held.
+func (r *RingBuffer) evict(n int) {
+	r.r = (r.r + n) % r.size
+	r.isFull = false
+	r.pos += int64(n)
+	r.dropped += uint64(n)
+}
+
+// DroppedBytes returns the number of bytes discarded by writes to a buffer
+// created with WithOverwrite, since it was created or ResetDroppedBytes was
+// last called. Reset does not clear the count.
+// A count that keeps growing means the reader is falling behind.
+func (r *RingBuffer) DroppedBytes() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
+
+// ResetDroppedBytes resets the count returned by DroppedBytes to zero.
+func (r *RingBuffer) ResetDroppedBytes() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dropped = 0
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"hash/crc32"
+	"testing"
+)
+
+func TestRingBuffer_Overwrite(t *testing.T) {
+	rb := New(4, WithOverwrite())
+
+	n, err := rb.Write([]byte("abc"))
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expect write 3 bytes but got %d", n)
+	}
+
+	// evicts "ab"
+	n, err = rb.Write([]byte("def"))
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expect write 3 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cdef")) {
+		t.Fatalf("expect cdef but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if rb.DroppedBytes() != 2 {
+		t.Fatalf("expect 2 dropped bytes but got %d", rb.DroppedBytes())
+	}
+
+	// evicts "c"
+	if err := rb.WriteByte('g'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("defg")) {
+		t.Fatalf("expect defg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// evicts "defg" and the first two bytes of the write
+	n, err = rb.WriteString("hijklm")
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n != 6 {
+		t.Fatalf("expect write 6 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("jklm")) {
+		t.Fatalf("expect jklm but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if rb.DroppedBytes() != 9 {
+		t.Fatalf("expect 9 dropped bytes but got %d", rb.DroppedBytes())
+	}
+
+	// atomic writes evict too
+	if err := rb.WriteUint16BE(0x6e6f); err != nil {
+		t.Fatalf("WriteUint16BE failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("lmno")) {
+		t.Fatalf("expect lmno but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	rb.Reset()
+	if rb.DroppedBytes() != 11 {
+		t.Fatalf("expect 11 dropped bytes but got %d", rb.DroppedBytes())
+	}
+	rb.ResetDroppedBytes()
+	if rb.DroppedBytes() != 0 {
+		t.Fatalf("expect 0 dropped bytes but got %d", rb.DroppedBytes())
+	}
+}
+
+func TestRingBuffer_OverwriteChecksum(t *testing.T) {
+	rb := New(4, WithOverwrite(), WithChecksum(crc32.IEEETable))
+
+	// the first 4 bytes are dropped unwritten, but still checksummed
+	if _, err := rb.Write([]byte("abcdefgh")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.WriteString("ijklmn"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if want := crc32.ChecksumIEEE([]byte("abcdefghijklmn")); rb.Checksum() != want {
+		t.Fatalf("expect checksum %#x but got %#x", want, rb.Checksum())
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("klmn")) {
+		t.Fatalf("expect klmn but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
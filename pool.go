@@ -0,0 +1,53 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "sync"
+
+// BufferPool is a pool of reusable RingBuffers, backed by a sync.Pool for
+// each buffer size. The zero value is ready to use.
+// A BufferPool is safe for concurrent use by multiple goroutines.
+type BufferPool struct {
+	pools sync.Map // map[int]*sync.Pool
+}
+
+// Get returns an empty RingBuffer of the given size from the pool,
+// allocating a new one if none is available.
+func (p *BufferPool) Get(size int) *RingBuffer {
+	if rb, ok := p.pool(size).Get().(*RingBuffer); ok {
+		return rb
+	}
+	return New(size)
+}
+
+// Put resets rb to the state of a buffer returned by New with its current
+// capacity, and returns it to the pool for buffers of that capacity.
+// Everything set by options or by methods during its previous use is
+// cleared: it is reopened if it was closed, and loses its deadlines, tee,
+// checksum, notification channel, hooks, statistics and counters.
+// Put must not be called while rb is still in use, and rb must not be used
+// after it has been returned to the pool.
+func (p *BufferPool) Put(rb *RingBuffer) {
+	rb.mu.Lock()
+	if rb.readTimer != nil {
+		rb.readTimer.Stop()
+	}
+	if rb.writeTimer != nil {
+		rb.writeTimer.Stop()
+	}
+	buf, cond := rb.buf, rb.cond
+	rb.mu.Unlock()
+
+	*rb = RingBuffer{buf: buf, size: len(buf), cond: cond}
+	p.pool(len(buf)).Put(rb)
+}
+
+func (p *BufferPool) pool(size int) *sync.Pool {
+	if v, ok := p.pools.Load(size); ok {
+		return v.(*sync.Pool)
+	}
+	v, _ := p.pools.LoadOrStore(size, new(sync.Pool))
+	return v.(*sync.Pool)
+}
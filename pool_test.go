@@ -0,0 +1,88 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"hash/crc32"
+	"testing"
+	"time"
+)
+
+func TestBufferPool(t *testing.T) {
+	var p BufferPool
+
+	rb := p.Get(16)
+	if rb.Capacity() != 16 {
+		t.Fatalf("expect capacity 16 but got %d", rb.Capacity())
+	}
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = rb.Close()
+	p.Put(rb)
+
+	// sync.Pool may drop items at any time, so only check what Get returns
+	for _, size := range []int{16, 32} {
+		rb = p.Get(size)
+		if rb.Capacity() != size {
+			t.Fatalf("expect capacity %d but got %d", size, rb.Capacity())
+		}
+		if !rb.IsEmpty() {
+			t.Fatalf("expect IsEmpty is true but got false")
+		}
+		if _, err := rb.Write([]byte("abcd")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		p.Put(rb)
+	}
+}
+
+func BenchmarkBufferPool(b *testing.B) {
+	var p BufferPool
+	data := make([]byte, 512)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rb := p.Get(1024)
+		if _, err := rb.Write(data); err != nil {
+			b.Fatalf("write failed: %v", err)
+		}
+		p.Put(rb)
+	}
+}
+
+func TestBufferPool_PutClearsState(t *testing.T) {
+	var p BufferPool
+
+	rb := p.Get(16)
+	rb.TeeTo(crc32.NewIEEE())
+	_ = rb.SetReadDeadline(time.Now().Add(-time.Second))
+	_ = rb.SetWriteDeadline(time.Now().Add(time.Hour))
+	rb.NotifyChan()
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	p.Put(rb)
+
+	if rb.tee != nil || rb.notify != nil {
+		t.Fatalf("expect no tee or notification channel after Put")
+	}
+	if !rb.readDeadline.IsZero() || !rb.writeDeadline.IsZero() || rb.readTimer != nil || rb.writeTimer != nil {
+		t.Fatalf("expect no deadlines after Put")
+	}
+	if !rb.IsEmpty() || rb.Capacity() != 16 {
+		t.Fatalf("expect an empty buffer of 16 bytes but got %d of %d", rb.Length(), rb.Capacity())
+	}
+
+	// blocking reads wait for data rather than time out
+	go func() {
+		time.Sleep(time.Millisecond)
+		_, _ = rb.Write([]byte("ab"))
+	}()
+	buf := make([]byte, 2)
+	if _, err := rb.BlockingRead(buf); err != nil {
+		t.Fatalf("BlockingRead failed: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "io"
+
+// ReadAt implements io.ReaderAt over the unread data, copying the bytes at
+// offset off from the oldest unread byte into p without consuming them.
+// If fewer than len(p) bytes are buffered from off, it copies those and
+// returns io.EOF. It returns ErrOutOfRange if off is negative.
+// Offsets are only valid until the next read, which moves the oldest
+// unread byte.
+func (r *RingBuffer) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, ErrOutOfRange
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	length := int64(r.length())
+	if off >= length {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > length-off {
+		p = p[:length-off]
+		err = io.EOF
+	}
+	start := (r.r + int(off)) % r.size
+	n = copy(p, r.buf[start:])
+	n += copy(p[n:], r.buf)
+	return n, err
+}
+
+// SectionReader returns an io.SectionReader over the unread data, using
+// ReadAt, for decoders that need random access to it. The section covers
+// the bytes that are unread when SectionReader is called, and nothing is
+// consumed by reading it. It is invalidated by any read from the buffer,
+// after which it returns the wrong bytes, and by a Reset or other method
+// that discards data.
+func (r *RingBuffer) SectionReader() *io.SectionReader {
+	return io.NewSectionReader(r, 0, int64(r.Length()))
+}
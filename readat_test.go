@@ -0,0 +1,81 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_ReadAt(t *testing.T) {
+	rb := New(8)
+	var _ io.ReaderAt = rb
+
+	if _, err := rb.ReadAt(make([]byte, 1), 0); err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// the bytes at offsets 1 to 4 wrap around
+	buf := make([]byte, 4)
+	n, err := rb.ReadAt(buf, 1)
+	if err != nil || !bytes.Equal(buf[:n], []byte("fghi")) {
+		t.Fatalf("expect fghi but got %q, %v. r.w=%d, r.r=%d", buf[:n], err, rb.w, rb.r)
+	}
+	n, err = rb.ReadAt(buf, 3)
+	if err != io.EOF || !bytes.Equal(buf[:n], []byte("hij")) {
+		t.Fatalf("expect hij and io.EOF but got %q, %v", buf[:n], err)
+	}
+	if _, err := rb.ReadAt(buf, -1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_SectionReader(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	sr := rb.SectionReader()
+	if sr.Size() != 6 {
+		t.Fatalf("expect size 6 but got %d", sr.Size())
+	}
+	// later writes are not part of the section
+	if _, err := rb.Write([]byte("kl")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if _, err := sr.Seek(2, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	p, err := io.ReadAll(sr)
+	if err != nil || !bytes.Equal(p, []byte("ghij")) {
+		t.Fatalf("expect ghij but got %q, %v", p, err)
+	}
+	if rb.Length() != 8 {
+		t.Fatalf("expect len 8 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// WithRecordPrefix sets the format of the length prefix of records written
+// by WriteRecord and read by ReadRecord: an unsigned integer of width bytes,
+// which must be 1, 2, 4 or 8, in the given byte order. A width of 1 has no
+// byte order, so order may be nil. The default is a 4-byte big-endian
+// prefix. WithRecordPrefix panics if width is not supported.
+func WithRecordPrefix(width int, order binary.ByteOrder) Option {
+	switch width {
+	case 1, 2, 4, 8:
+	default:
+		panic("ringbuffer: unsupported record prefix width")
+	}
+	return func(r *RingBuffer) {
+		r.recordWidth = width
+		r.recordOrder = order
+	}
+}
+
+// recordPrefixLen returns the size of the length prefix of a record.
+func (r *RingBuffer) recordPrefixLen() int {
+	if r.recordWidth == 0 {
+		return 4
+	}
+	return r.recordWidth
+}
+
+// maxRecordLen returns the largest payload length the prefix can represent.
+func (r *RingBuffer) maxRecordLen() uint64 {
+	return math.MaxUint64 >> (64 - 8*r.recordPrefixLen())
+}
+
+// putRecordPrefix writes the prefix for a payload of n bytes into prefix,
+// which must be recordPrefixLen bytes long.
+func (r *RingBuffer) putRecordPrefix(prefix []byte, n uint64) {
+	order := r.recordOrder
+	if order == nil {
+		order = binary.BigEndian
+	}
+	switch len(prefix) {
+	case 1:
+		prefix[0] = byte(n)
+	case 2:
+		order.PutUint16(prefix, uint16(n))
+	case 4:
+		order.PutUint32(prefix, uint32(n))
+	default:
+		order.PutUint64(prefix, n)
+	}
+}
+
+// recordPrefix decodes a prefix written by putRecordPrefix.
+func (r *RingBuffer) recordPrefix(prefix []byte) uint64 {
+	order := r.recordOrder
+	if order == nil {
+		order = binary.BigEndian
+	}
+	switch len(prefix) {
+	case 1:
+		return uint64(prefix[0])
+	case 2:
+		return uint64(order.Uint16(prefix))
+	case 4:
+		return uint64(order.Uint32(prefix))
+	default:
+		return order.Uint64(prefix)
+	}
+}
+
+// WriteRecord writes p to the buffer as a record, prefixed with its length,
+// by default as a 4-byte big-endian integer. See WithRecordPrefix.
+// Either the whole record is written, or nothing is written and ErrFull
+// returned. Records larger than the prefix can represent, such as
+// math.MaxUint32 bytes for the default prefix, return ErrTooLarge,
+// but in practice a record must fit in Capacity() bytes with its prefix.
+func (r *RingBuffer) WriteRecord(p []byte) error {
+	if uint64(len(p)) > r.maxRecordLen() {
+		return ErrTooLarge
+	}
+	var buf [8]byte
+	prefix := buf[:r.recordPrefixLen()]
+	r.putRecordPrefix(prefix, uint64(len(p)))
+
+	r.mu.Lock()
+	err := r.writeVectored([][]byte{prefix, p}, len(prefix)+len(p))
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
+
+// ReadRecord reads a record written by WriteRecord and returns its payload.
+// If a complete record is not buffered yet, it returns ErrEmpty without
+// consuming anything, or ErrClosed if the buffer is closed.
+func (r *RingBuffer) ReadRecord() ([]byte, error) {
+	r.mu.Lock()
+	p, err := r.readRecord()
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return p, err
+}
+
+// RecordReader consumes the length prefix of the next record and returns an
+// io.Reader for its payload, which consumes the payload from the buffer as
+// it is read and returns io.EOF at the end of the record, so the payload
+// can be streamed into a decoder without copying it out first. The payload
+// need not be fully buffered: like LimitReader, the reader returns ErrEmpty
+// until more of it is written.
+// It returns ErrEmpty if the whole prefix is not buffered yet, or ErrClosed
+// if the buffer is also closed, without consuming anything. It returns
+// ErrTooLarge, also without consuming anything, if the prefix is larger
+// than the largest int.
+func (r *RingBuffer) RecordReader() (io.Reader, error) {
+	r.mu.Lock()
+	var buf [8]byte
+	prefix := buf[:r.recordPrefixLen()]
+	if r.length() < len(prefix) {
+		err := r.emptyErr()
+		r.mu.Unlock()
+		return nil, err
+	}
+	r.peek(prefix)
+	n := r.recordPrefix(prefix)
+	if n > math.MaxInt {
+		r.mu.Unlock()
+		return nil, ErrTooLarge
+	}
+	r.consumed(prefix)
+	r.advance(len(prefix))
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return &limitReader{r, int(n)}, nil
+}
+
+// readRecord is ReadRecord without locking. It must be called with r.mu held.
+func (r *RingBuffer) readRecord() ([]byte, error) {
+	n, ok := r.recordLen()
+	if !ok {
+		return nil, r.emptyErr()
+	}
+
+	var buf [8]byte
+	prefix := buf[:r.recordPrefixLen()]
+	r.peek(prefix)
+	r.consumed(prefix)
+	r.advance(len(prefix))
+
+	p := make([]byte, n)
+	_, _ = r.read(p)
+	return p, nil
+}
+
+// recordLen returns the payload length of the next record,
+// and whether the whole record is buffered. It must be called with r.mu held.
+func (r *RingBuffer) recordLen() (int, bool) {
+	length, prefixLen := r.length(), r.recordPrefixLen()
+	if length < prefixLen {
+		return 0, false
+	}
+	var buf [8]byte
+	prefix := buf[:prefixLen]
+	r.peek(prefix)
+	n := r.recordPrefix(prefix)
+	if n > uint64(length-prefixLen) {
+		return 0, false
+	}
+	return int(n), true
+}
@@ -0,0 +1,199 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_Record(t *testing.T) {
+	rb := New(16)
+
+	if _, err := rb.ReadRecord(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	if err := rb.WriteRecord([]byte("hello")); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+	if err := rb.WriteRecord(nil); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+	if rb.Length() != 13 {
+		t.Fatalf("expect len 13 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	// doesn't fit, nothing is written
+	if err := rb.WriteRecord([]byte("x")); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if rb.Length() != 13 {
+		t.Fatalf("expect len 13 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	p, err := rb.ReadRecord()
+	if err != nil {
+		t.Fatalf("ReadRecord failed: %v", err)
+	}
+	if !bytes.Equal(p, []byte("hello")) {
+		t.Fatalf("expect hello but got %q", p)
+	}
+	p, err = rb.ReadRecord()
+	if err != nil {
+		t.Fatalf("ReadRecord failed: %v", err)
+	}
+	if len(p) != 0 {
+		t.Fatalf("expect empty record but got %q", p)
+	}
+
+	// record wraps around the end of the buffer
+	if err := rb.WriteRecord([]byte("wrapped")); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+	p, err = rb.ReadRecord()
+	if err != nil {
+		t.Fatalf("ReadRecord failed: %v", err)
+	}
+	if !bytes.Equal(p, []byte("wrapped")) {
+		t.Fatalf("expect wrapped but got %q", p)
+	}
+}
+
+func TestRingBuffer_RecordPartial(t *testing.T) {
+	rb := New(16)
+
+	// a prefix for a 5-byte record followed by 2 bytes of payload
+	if _, err := rb.Write([]byte{0, 0, 0, 5, 'a', 'b'}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.ReadRecord(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	if _, err := rb.Write([]byte("cde")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	p, err := rb.ReadRecord()
+	if err != nil {
+		t.Fatalf("ReadRecord failed: %v", err)
+	}
+	if !bytes.Equal(p, []byte("abcde")) {
+		t.Fatalf("expect abcde but got %q", p)
+	}
+
+	// a partial record can't complete once closed
+	if _, err := rb.Write([]byte{0, 0}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = rb.Close()
+	if _, err := rb.ReadRecord(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_RecordPrefix(t *testing.T) {
+	tests := []struct {
+		width  int
+		order  binary.ByteOrder
+		prefix []byte
+	}{
+		{1, nil, []byte{3}},
+		{2, binary.LittleEndian, []byte{3, 0}},
+		{2, binary.BigEndian, []byte{0, 3}},
+		{4, binary.LittleEndian, []byte{3, 0, 0, 0}},
+		{8, binary.BigEndian, []byte{0, 0, 0, 0, 0, 0, 0, 3}},
+	}
+	for _, tt := range tests {
+		rb := New(16, WithRecordPrefix(tt.width, tt.order))
+		if err := rb.WriteRecord([]byte("abc")); err != nil {
+			t.Fatalf("width %d: WriteRecord failed: %v", tt.width, err)
+		}
+		if !bytes.Equal(rb.Bytes(), append(tt.prefix, "abc"...)) {
+			t.Fatalf("width %d: expect %q but got %q", tt.width, append(tt.prefix, "abc"...), rb.Bytes())
+		}
+		p, err := rb.ReadRecord()
+		if err != nil {
+			t.Fatalf("width %d: ReadRecord failed: %v", tt.width, err)
+		}
+		if !bytes.Equal(p, []byte("abc")) {
+			t.Fatalf("width %d: expect abc but got %q", tt.width, p)
+		}
+	}
+
+	rb := New(1024, WithRecordPrefix(1, nil))
+	if err := rb.WriteRecord(make([]byte, 256)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expect ErrTooLarge but got %v", err)
+	}
+	if err := rb.WriteRecord(make([]byte, 255)); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+	rb = New(1<<17, WithRecordPrefix(2, binary.LittleEndian))
+	if err := rb.WriteRecord(make([]byte, 1<<16)); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expect ErrTooLarge but got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expect a panic for width 3")
+		}
+	}()
+	WithRecordPrefix(3, binary.BigEndian)
+}
+
+func TestRingBuffer_RecordReader(t *testing.T) {
+	rb := New(16)
+
+	if _, err := rb.RecordReader(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.Write([]byte{0, 0}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.RecordReader(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if rb.Length() != 2 {
+		t.Fatalf("expect len 2 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	// a prefix for a 5-byte record, 3 bytes of payload and the next record
+	if _, err := rb.Write([]byte{0, 5, 'a', 'b', 'c'}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	pr, err := rb.RecordReader()
+	if err != nil {
+		t.Fatalf("RecordReader failed: %v", err)
+	}
+	buf := make([]byte, 8)
+	n, err := pr.Read(buf)
+	if err != nil || !bytes.Equal(buf[:n], []byte("abc")) {
+		t.Fatalf("expect abc but got %q, %v", buf[:n], err)
+	}
+	if _, err := pr.Read(buf); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	if _, err := rb.Write([]byte("de")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := rb.WriteRecord([]byte("next")); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+	p, err := io.ReadAll(pr)
+	if err != nil || !bytes.Equal(p, []byte("de")) {
+		t.Fatalf("expect de but got %q, %v", p, err)
+	}
+
+	if p, err = rb.ReadRecord(); err != nil || !bytes.Equal(p, []byte("next")) {
+		t.Fatalf("expect next but got %q, %v", p, err)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// RecordBuffer is a queue of fixed-size records backed by a RingBuffer.
+// Records are stored back to back without framing, so pushing and popping
+// a record are O(1) and memory use is exactly recordSize*count bytes.
+// It is safe for concurrent use by multiple goroutines.
+type RecordBuffer struct {
+	rb         *RingBuffer
+	recordSize int
+}
+
+// NewRecordBuffer returns a new RecordBuffer that holds up to count records
+// of recordSize bytes each.
+func NewRecordBuffer(recordSize, count int) *RecordBuffer {
+	return &RecordBuffer{
+		rb:         New(recordSize * count),
+		recordSize: recordSize,
+	}
+}
+
+// Push appends a record to the queue.
+// It returns ErrRecordSize if len(p) is not the record size,
+// and ErrFull if the queue is full.
+func (b *RecordBuffer) Push(p []byte) error {
+	if len(p) != b.recordSize {
+		return ErrRecordSize
+	}
+
+	b.rb.mu.Lock()
+	defer b.rb.mu.Unlock()
+	return b.rb.writeAll(p)
+}
+
+// Pop removes and returns the oldest record in the queue.
+// It returns ErrEmpty if the queue is empty.
+func (b *RecordBuffer) Pop() ([]byte, error) {
+	b.rb.mu.Lock()
+	defer b.rb.mu.Unlock()
+
+	if b.rb.length() < b.recordSize {
+		return nil, b.rb.emptyErr()
+	}
+	p := make([]byte, b.recordSize)
+	_, _ = b.rb.read(p)
+	return p, nil
+}
+
+// Len returns the number of records in the queue.
+func (b *RecordBuffer) Len() int {
+	if b.recordSize == 0 {
+		return 0
+	}
+	return b.rb.Length() / b.recordSize
+}
+
+// Cap returns the maximum number of records the queue can hold.
+func (b *RecordBuffer) Cap() int {
+	if b.recordSize == 0 {
+		return 0
+	}
+	return b.rb.Capacity() / b.recordSize
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRecordBuffer(t *testing.T) {
+	b := NewRecordBuffer(4, 2)
+
+	if b.Cap() != 2 {
+		t.Fatalf("expect cap 2 but got %d", b.Cap())
+	}
+	if _, err := b.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if err := b.Push([]byte("abc")); !errors.Is(err, ErrRecordSize) {
+		t.Fatalf("expect ErrRecordSize but got %v", err)
+	}
+
+	for _, p := range []string{"aaaa", "bbbb"} {
+		if err := b.Push([]byte(p)); err != nil {
+			t.Fatalf("push failed: %v", err)
+		}
+	}
+	if err := b.Push([]byte("cccc")); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if b.Len() != 2 {
+		t.Fatalf("expect len 2 but got %d", b.Len())
+	}
+
+	// cycle records through the queue
+	for _, want := range []string{"aaaa", "bbbb", "cccc", "dddd"} {
+		p, err := b.Pop()
+		if err != nil {
+			t.Fatalf("pop failed: %v", err)
+		}
+		if !bytes.Equal(p, []byte(want)) {
+			t.Fatalf("expect %s but got %s", want, p)
+		}
+		next := bytes.Repeat([]byte{want[0] + 2}, 4)
+		if err := b.Push(next); err != nil {
+			t.Fatalf("push failed: %v", err)
+		}
+	}
+	if b.Len() != 2 {
+		t.Fatalf("expect len 2 but got %d", b.Len())
+	}
+}
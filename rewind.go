@@ -0,0 +1,62 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "bufio"
+
+// Rewind moves the read position back by n bytes, so that bytes that have
+// already been read are read again.
+// Read bytes stay in the buffer until writes overwrite them, oldest first,
+// so Rewind can go back at most Replayable bytes. It returns ErrOutOfRange,
+// without moving the read position, if n is negative or larger than that.
+func (r *RingBuffer) Rewind(n int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n < 0 || n > r.replay {
+		return ErrOutOfRange
+	}
+	r.rewind(n)
+	return nil
+}
+
+// UnreadByte unreads the last byte consumed, so that it is returned by the
+// next read, implementing io.ByteScanner.
+// Only the last byte of the most recent read can be unread, and only if
+// nothing else has changed the buffer since: calling UnreadByte twice in a
+// row, or after a write, returns bufio.ErrInvalidUnreadByte. Use Rewind to
+// go back further.
+func (r *RingBuffer) UnreadByte() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.unread == 0 {
+		return bufio.ErrInvalidUnreadByte
+	}
+	r.rewind(1)
+	return nil
+}
+
+// rewind moves the read position back by n bytes, which must be no more
+// than r.replay. It must be called with r.mu held.
+func (r *RingBuffer) rewind(n int) {
+	if n == 0 {
+		return
+	}
+	r.r = (r.r - n + r.size) % r.size
+	r.pos -= int64(n)
+	r.replay -= n
+	if r.r == r.w {
+		r.isFull = true
+	}
+	r.changed()
+}
+
+// Replayable returns the number of bytes that Rewind can go back.
+func (r *RingBuffer) Replayable() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.replay
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRingBuffer_Rewind(t *testing.T) {
+	rb := New(8)
+
+	if err := rb.Rewind(1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if rb.Replayable() != 4 {
+		t.Fatalf("expect 4 replayable bytes but got %d", rb.Replayable())
+	}
+	if err := rb.Rewind(2); err != nil {
+		t.Fatalf("rewind failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cdef")) {
+		t.Fatalf("expect cdef but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// writes overwrite the oldest read bytes first: "gh" fills the end
+	// of the buffer, then "i" overwrites "a"
+	if _, err := rb.Write([]byte("ghi")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.Replayable() != 1 {
+		t.Fatalf("expect 1 replayable byte but got %d", rb.Replayable())
+	}
+	if err := rb.Rewind(2); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	if err := rb.Rewind(1); err != nil {
+		t.Fatalf("rewind failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("bcdefghi")) {
+		t.Fatalf("expect bcdefghi but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false")
+	}
+
+	// rewinding across the wrap
+	if _, err := rb.Read(make([]byte, 8)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := rb.Rewind(3); err != nil {
+		t.Fatalf("rewind failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("ghi")) {
+		t.Fatalf("expect ghi but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	rb.Reset()
+	if rb.Replayable() != 0 {
+		t.Fatalf("expect 0 replayable bytes but got %d", rb.Replayable())
+	}
+}
+
+func TestRingBuffer_RewindCompact(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 2)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	// compacting keeps the read bytes behind r
+	rb.Compact()
+	if err := rb.Rewind(2); err != nil {
+		t.Fatalf("rewind failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcdef")) {
+		t.Fatalf("expect abcdef but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// compacting an empty buffer discards them
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	rb.Compact()
+	if err := rb.Rewind(1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+}
+
+func TestRingBuffer_UnreadByte(t *testing.T) {
+	rb := New(4)
+
+	if err := rb.UnreadByte(); !errors.Is(err, bufio.ErrInvalidUnreadByte) {
+		t.Fatalf("expect bufio.ErrInvalidUnreadByte but got %v", err)
+	}
+
+	// r wraps back to the end of the buffer
+	if _, err := rb.Write([]byte("abcd")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 3)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("e")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if b, _ := rb.ReadByte(); b != 'd' {
+		t.Fatalf("expect d but got %c", b)
+	}
+	if b, _ := rb.ReadByte(); b != 'e' {
+		t.Fatalf("expect e but got %c", b)
+	}
+	if err := rb.UnreadByte(); err != nil {
+		t.Fatalf("UnreadByte failed: %v", err)
+	}
+	if b, _ := rb.ReadByte(); b != 'e' {
+		t.Fatalf("expect e but got %c", b)
+	}
+
+	// only one byte can be unread
+	if err := rb.UnreadByte(); err != nil {
+		t.Fatalf("UnreadByte failed: %v", err)
+	}
+	if err := rb.UnreadByte(); !errors.Is(err, bufio.ErrInvalidUnreadByte) {
+		t.Fatalf("expect bufio.ErrInvalidUnreadByte but got %v", err)
+	}
+
+	// after Read, the last byte read is unread
+	buf := make([]byte, 4)
+	if _, err := rb.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := rb.UnreadByte(); err != nil {
+		t.Fatalf("UnreadByte failed: %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("e")) {
+		t.Fatalf("expect e but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// writes invalidate the unread
+	if _, err := rb.ReadByte(); err != nil {
+		t.Fatalf("ReadByte failed: %v", err)
+	}
+	if err := rb.WriteByte('f'); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	if err := rb.UnreadByte(); !errors.Is(err, bufio.ErrInvalidUnreadByte) {
+		t.Fatalf("expect bufio.ErrInvalidUnreadByte but got %v", err)
+	}
+}
@@ -5,33 +5,145 @@
 package ringbuffer
 
 import (
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
 	"sync"
-	"unsafe"
+	"time"
 )
 
+// MaxSize is the largest size of a buffer. Positions in the buffer are
+// added together, which must not overflow an int.
+// In practice, the largest buffer that can be allocated is usually smaller.
+const MaxSize = math.MaxInt / 2
+
 var (
 	ErrFull  = errors.New("ringbuffer is full")
 	ErrEmpty = errors.New("ringbuffer is empty")
+
+	// ErrShortWrite is returned when only part of a write fit in the buffer.
+	// It wraps io.ErrShortWrite.
+	ErrShortWrite = fmt.Errorf("ringbuffer short write: %w", io.ErrShortWrite)
+
+	// ErrClosed is returned by writes to a closed buffer,
+	// and by reads once a closed buffer has been drained.
+	ErrClosed = errors.New("ringbuffer is closed")
+
+	// ErrOutOfRange is returned when an offset falls outside the unread data.
+	ErrOutOfRange = errors.New("ringbuffer offset out of range")
+
+	// ErrExceedsCapacity is returned when a request could never be satisfied
+	// because it exceeds the capacity of the buffer.
+	ErrExceedsCapacity = errors.New("ringbuffer request exceeds capacity")
+
+	// ErrTooLarge is returned when a record is too large to be framed.
+	ErrTooLarge = errors.New("ringbuffer record too large")
+
+	// ErrRecordSize is returned when a record pushed to a RecordBuffer
+	// is not exactly the buffer's record size.
+	ErrRecordSize = errors.New("ringbuffer record has the wrong size")
+
+	// ErrTokenTooLong is returned when a delimiter is not found within the
+	// maximum number of bytes allowed before it.
+	ErrTokenTooLong = errors.New("ringbuffer token too long")
 )
 
 // RingBuffer is a circular buffer safe for concurrent use by multiple goroutines.
 // It implements io.ReadWriter, io.StringWriter, io.ByteWriter, & io.ByteReader.
 type RingBuffer struct {
-	buf    []byte
-	size   int
-	r      int // next position to read
-	w      int // next position to write
-	isFull bool
-	mu     sync.Mutex
+	buf     []byte
+	size    int
+	r       int   // next position to read
+	w       int   // next position to write
+	replay  int   // bytes before r that have been read but not overwritten
+	unread  int   // bytes consumed by the last operation, if it was a read
+	runeLen int   // size of the rune read by the last operation, if it was ReadRune
+	pos     int64 // stream offset of the next byte to read, for Seek
+	isFull  bool
+	closed  bool
+	mu      locker
+	cond    *sync.Cond // signalled when data is read or written
+
+	readDeadline  time.Time
+	writeDeadline time.Time
+	readTimer     *time.Timer
+	writeTimer    *time.Timer
+	spins         int // times to yield before blocking
+	maxReadChunk  int // bytes copied by a read at most, if positive
+
+	maxSize    int                                   // size limit for growable buffers
+	growPolicy func(current, needed int) (int, bool) // nil for doubling
+	strict     bool                                  // writes are all or nothing
+	eofOnEmpty bool                                  // reads return io.EOF instead of ErrEmpty
+
+	recordWidth int              // bytes in a record length prefix, 0 for 4
+	recordOrder binary.ByteOrder // byte order of record length prefixes, nil for big-endian
+
+	overwrite bool   // writes evict the oldest unread bytes
+	dropped   uint64 // bytes evicted by overwriting writes
+	pinned    int    // unread bytes held by DrainSegments, not to be evicted
+	drains    uint64 // DrainSegments calls, so that stale releases do nothing
+
+	crcTable *crc32.Table // nil unless checksumming is enabled
+	crc      uint32
+
+	tee hash.Hash // receives consumed bytes, if set
+
+	notify chan struct{} // signalled after writes, if requested
+
+	msgs []int // lengths of buffered messages, oldest first
+
+	stats      *stats   // nil unless statistics are enabled
+	writeSizes []uint64 // histogram of write sizes, nil unless enabled
+
+	onFull       func()
+	onEmpty      func()
+	fullReported bool // onFull was called and the buffer hasn't been empty since
+	emptyDue     bool // the buffer has held data since onEmpty was last called
+
+	onGrow   func(oldCap, newCap int)
+	grown    bool // the buffer grew since onGrow was last due
+	grewFrom int  // size before the buffer grew
 }
 
 // New returns a new RingBuffer whose buffer has the given size.
-func New(size int) *RingBuffer {
-	return &RingBuffer{
-		buf:  make([]byte, size),
+// A buffer of size 0 can't hold any data: writes return ErrFull, and reads
+// ErrEmpty, unless it was created by NewGrowable with a larger maximum.
+// New panics if size is negative or larger than MaxSize, or if a buffer of
+// that size can't be allocated.
+func New(size int, opts ...Option) *RingBuffer {
+	r := &RingBuffer{
 		size: size,
 	}
+	r.cond = sync.NewCond(&r.mu)
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.size < 0 {
+		panic(fmt.Sprintf("ringbuffer: negative size %d", r.size))
+	}
+	if r.size > MaxSize {
+		panic(fmt.Sprintf("ringbuffer: size %d is larger than MaxSize", r.size))
+	}
+	r.buf = makeBuf(r.size)
+	r.updateStats()
+	return r
+}
+
+// makeBuf allocates a buffer of the given size, turning the runtime's panic
+// for a size it can never allocate, which may be well below MaxSize, into one
+// that says where it came from.
+func makeBuf(size int) []byte {
+	defer func() {
+		if err := recover(); err != nil {
+			panic(fmt.Sprintf("ringbuffer: can't allocate a buffer of size %d: %v", size, err))
+		}
+	}()
+	return make([]byte, size)
 }
 
 // Read reads up to len(p) bytes into p.
@@ -42,72 +154,181 @@ func (r *RingBuffer) Read(p []byte) (n int, err error) {
 	}
 
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	n, err = r.read(r.chunk(p, 0))
+	hook := r.emptyHook()
+	r.mu.Unlock()
 
-	if r.w == r.r && !r.isFull {
-		return 0, ErrEmpty
+	if hook != nil {
+		hook()
 	}
+	return n, err
+}
 
-	if r.w > r.r {
-		n = r.w - r.r
-		if n > len(p) {
-			n = len(p)
-		}
-		copy(p, r.buf[r.r:r.r+n])
-		r.r = (r.r + n) % r.size
-		return
+// chunk returns p, shortened to the WithMaxReadChunk limit if there is one,
+// but no shorter than min bytes. It must be called with r.mu held.
+func (r *RingBuffer) chunk(p []byte, min int) []byte {
+	n := r.maxReadChunk
+	if n < min {
+		n = min
 	}
+	if n > 0 && len(p) > n {
+		return p[:n]
+	}
+	return p
+}
 
-	n = r.size - r.r + r.w
-	if n > len(p) {
-		n = len(p)
+// read is Read without locking. It must be called with r.mu held.
+func (r *RingBuffer) read(p []byte) (n int, err error) {
+	switch {
+	case r.w > r.r:
+		// Fast path: the unread data doesn't wrap, so it takes a single copy,
+		// which also bounds n by len(p).
+		n = copy(p, r.buf[r.r:r.w])
+	case r.w == r.r && !r.isFull:
+		return 0, r.emptyErr()
+	default:
+		n = r.peek(p)
 	}
 
-	if r.r+n <= r.size {
-		copy(p, r.buf[r.r:r.r+n])
-	} else {
-		// Buffer wraps around. Read in two parts.
-		c1 := r.size - r.r
-		copy(p, r.buf[r.r:r.size])
-		c2 := n - c1
-		copy(p[c1:], r.buf[0:c2])
+	r.consumed(p[:n])
+	r.advance(n)
+	return n, nil
+}
+
+// advance moves the read pointer past n unread bytes.
+// It must be called with r.mu held.
+func (r *RingBuffer) advance(n int) {
+	if n == 0 {
+		return
 	}
 	r.r = (r.r + n) % r.size
-
 	r.isFull = false
-
-	return
+	r.pos += int64(n)
+	r.replay += n
+	if r.pinned > n {
+		r.pinned -= n
+	} else {
+		r.pinned = 0
+	}
+	r.changed()
+	r.unread = n
 }
 
 // ReadByte reads and returns the next byte from the input or ErrEmpty.
 func (r *RingBuffer) ReadByte() (b byte, err error) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	b, err = r.readByte()
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return b, err
+}
+
+// TryReadByte is like ReadByte, but reports whether a byte was read instead
+// of returning an error, for polling loops that only care whether one was
+// available. It returns false both when the buffer is empty and when it is
+// closed and drained.
+func (r *RingBuffer) TryReadByte() (byte, bool) {
+	b, err := r.ReadByte()
+	return b, err == nil
+}
 
+// readByte is ReadByte without locking. It must be called with r.mu held.
+func (r *RingBuffer) readByte() (b byte, err error) {
 	if r.w == r.r && !r.isFull {
-		return 0, ErrEmpty
+		return 0, r.emptyErr()
 	}
 	b = r.buf[r.r]
-	r.r++
-	if r.r == r.size {
-		r.r = 0
-	}
-
-	r.isFull = false
+	r.consumed(r.buf[r.r : r.r+1])
+	r.advance(1)
 	return b, err
 }
 
 // Write writes len(p) bytes from p to the underlying buffer.
-// It returns ErrFull if the buffer is full.
+// If p does not fit, it writes as much of p as fits and returns
+// ErrShortWrite, or ErrFull if the buffer was already full.
+// If the buffer was created with WithStrictWriter, it writes nothing and
+// returns ErrFull unless all of p fits.
 func (r *RingBuffer) Write(p []byte) (n int, err error) {
+	n, _, err = writeData(r, p)
+	return n, err
+}
+
+// WriteReportFull is Write, but also reports whether the write filled the
+// buffer, so that a producer can pause without calling IsFull, whose answer
+// may already be stale if a reader has since made space.
+func (r *RingBuffer) WriteReportFull(p []byte) (n int, becameFull bool, err error) {
+	return writeData(r, p)
+}
+
+// writeData implements Write, WriteString and WriteReportFull.
+func writeData[T string | []byte](r *RingBuffer, p T) (n int, becameFull bool, err error) {
 	if len(p) == 0 {
-		return 0, nil
+		return 0, false, nil
 	}
 
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	wasFull := r.isFull
+	n, err = writeChecked(r, p)
+	becameFull = r.isFull && !wasFull
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, becameFull, err
+}
+
+// writeChecked is writeData without locking: it writes p as Write does,
+// honouring WithStrictWriter.
+// It must be called with r.mu held.
+func writeChecked[T string | []byte](r *RingBuffer, p T) (n int, err error) {
+	r.recordWriteSize(len(p))
+	if r.strict {
+		if err = r.fits(len(p)); err != nil {
+			return 0, err
+		}
+	}
+	return copyIn(r, p)
+}
 
-	if r.isFull {
+// write is Write without locking. It must be called with r.mu held.
+func (r *RingBuffer) write(p []byte) (n int, err error) {
+	return copyIn(r, p)
+}
+
+// copyIn copies as much of p as fits into the buffer.
+// It must be called with r.mu held.
+func copyIn[T string | []byte](r *RingBuffer, p T) (n int, err error) {
+	if r.closed {
+		return 0, ErrClosed
+	}
+	if len(p) > r.free() && r.size < r.maxSize {
+		r.grow(r.length() + len(p))
+	}
+
+	// Bytes that would be overwritten by the end of p are dropped unwritten.
+	var skipped int
+	if r.overwrite && r.size > 0 && r.pinned == 0 {
+		if len(p) > r.size {
+			skipped = len(p) - r.size
+			r.dropped += uint64(skipped)
+			if r.crcTable != nil {
+				// The checksum covers dropped bytes too.
+				r.updateChecksum([]byte(p[:skipped]))
+			}
+			p = p[skipped:]
+		}
+		if free := r.free(); len(p) > free {
+			r.evict(len(p) - free)
+		}
+	}
+
+	if r.free() == 0 {
 		return 0, ErrFull
 	}
 
@@ -119,7 +340,7 @@ func (r *RingBuffer) Write(p []byte) (n int, err error) {
 	}
 
 	if len(p) > avail {
-		err = ErrFull
+		err = ErrShortWrite
 		p = p[:avail]
 	}
 	n = len(p)
@@ -128,15 +349,19 @@ func (r *RingBuffer) Write(p []byte) (n int, err error) {
 		c1 := r.size - r.w
 		if c1 >= n {
 			copy(r.buf[r.w:], p)
+			r.updateChecksum(r.buf[r.w : r.w+n])
 			r.w += n
 		} else {
 			copy(r.buf[r.w:], p[:c1])
 			c2 := n - c1
 			copy(r.buf[0:], p[c1:])
+			r.updateChecksum(r.buf[r.w:])
+			r.updateChecksum(r.buf[:c2])
 			r.w = c2
 		}
 	} else {
 		copy(r.buf[r.w:], p)
+		r.updateChecksum(r.buf[r.w : r.w+n])
 		r.w += n
 	}
 
@@ -146,19 +371,77 @@ func (r *RingBuffer) Write(p []byte) (n int, err error) {
 	if r.w == r.r {
 		r.isFull = true
 	}
+	r.changed()
+	r.signal()
 
-	return n, err
+	return n + skipped, err
+}
+
+// fits returns nil if n bytes fit in the buffer, growing it if needed,
+// ErrFull if they don't fit, or ErrClosed if the buffer is closed.
+// It must be called with r.mu held.
+func (r *RingBuffer) fits(n int) error {
+	if r.closed {
+		return ErrClosed
+	}
+	if n > r.free() && r.size < r.maxSize {
+		r.grow(r.length() + n)
+	}
+	if r.overwrite && r.pinned == 0 && n <= r.size {
+		return nil
+	}
+	if n > r.free() {
+		return ErrFull
+	}
+	return nil
+}
+
+// writeAll writes all of p or nothing, returning ErrFull if p does not fit.
+// It must be called with r.mu held.
+func (r *RingBuffer) writeAll(p []byte) error {
+	if err := r.fits(len(p)); err != nil {
+		return err
+	}
+	_, err := r.write(p)
+	return err
 }
 
 // WriteByte writes one byte into buffer, and returns ErrFull if buffer is full.
 func (r *RingBuffer) WriteByte(c byte) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	err := r.writeByte(c)
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
+
+// TryWriteByte is like WriteByte, but reports whether c was written instead
+// of returning an error. It returns false both when the buffer is full and
+// when it is closed.
+func (r *RingBuffer) TryWriteByte(c byte) bool {
+	return r.WriteByte(c) == nil
+}
 
-	if r.w == r.r && r.isFull {
+// writeByte is WriteByte without locking. It must be called with r.mu held.
+func (r *RingBuffer) writeByte(c byte) error {
+	if r.closed {
+		return ErrClosed
+	}
+	if r.free() == 0 && r.size < r.maxSize {
+		r.grow(r.size + 1)
+	}
+	if r.isFull && r.overwrite && r.pinned == 0 {
+		r.evict(1)
+	}
+	if r.free() == 0 {
 		return ErrFull
 	}
 	r.buf[r.w] = c
+	r.updateChecksum(r.buf[r.w : r.w+1])
 	r.w++
 
 	if r.w == r.size {
@@ -167,15 +450,106 @@ func (r *RingBuffer) WriteByte(c byte) error {
 	if r.w == r.r {
 		r.isFull = true
 	}
+	r.changed()
+	r.signal()
 
 	return nil
 }
 
+// changed wakes goroutines waiting on the buffer, and updates statistics,
+// after data is read or written. It must be called with r.mu held.
+func (r *RingBuffer) changed() {
+	// Writes overwrite read bytes starting with the oldest.
+	if free := r.free(); r.replay > free {
+		r.replay = free
+	}
+	r.unread = 0
+	r.runeLen = 0
+	if r.isFull || r.w != r.r {
+		r.emptyDue = true
+	} else {
+		r.fullReported = false
+	}
+	r.updateStats()
+	r.cond.Broadcast()
+
+	if debug {
+		if err := r.checkInvariants(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// fullHook records a transition to the full state and returns the OnFull
+// callback if it is due. Repeated transitions to full without the buffer
+// becoming empty in between are reported only once. Since only writes grow the
+// buffer, the returned callback also runs the OnGrow callback if it is due.
+// It must be called with r.mu held, and the callback invoked after unlocking.
+func (r *RingBuffer) fullHook() func() {
+	var onFull func()
+	if r.isFull && !r.fullReported {
+		r.fullReported = true
+		onFull = r.onFull
+	}
+	onGrow := r.growHook()
+	if onGrow == nil {
+		return onFull
+	}
+	return func() {
+		onGrow()
+		if onFull != nil {
+			onFull()
+		}
+	}
+}
+
+// emptyHook records a transition to the empty state and returns the OnEmpty
+// callback if it is due: the buffer is empty, and has held data since the
+// callback was last returned. It must be called with r.mu held, and the
+// callback invoked after unlocking.
+func (r *RingBuffer) emptyHook() func() {
+	if r.isFull || r.w != r.r || !r.emptyDue {
+		return nil
+	}
+	r.emptyDue = false
+	return r.onEmpty
+}
+
 // Length return the length of available read bytes.
+// The result is a snapshot: concurrent reads and writes may change the
+// length before the caller acts on it. Use LengthAndBytes to get the length
+// together with the data, or size reads with Read's return value instead.
 func (r *RingBuffer) Length() int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	return r.length()
+}
+
+// Len is an alias for Length, matching bytes.Buffer.
+func (r *RingBuffer) Len() int {
+	return r.Length()
+}
+
+// LengthAndBytes returns the length of available read bytes and a copy of
+// them, taken under a single lock so that they are consistent with
+// each other. Like Bytes, it does not change the read pointer.
+func (r *RingBuffer) LengthAndBytes() (int, []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.length(), r.bytes()
+}
+
+// LengthFree returns the length of available read bytes and the length of
+// available bytes to write, taken under a single lock so that they always
+// add up to Capacity(), unlike separate calls to Length and Free.
+func (r *RingBuffer) LengthFree() (length, free int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.length(), r.free()
+}
 
+// length is Length without locking. It must be called with r.mu held.
+func (r *RingBuffer) length() int {
 	if r.w == r.r {
 		if r.isFull {
 			return r.size
@@ -191,15 +565,29 @@ func (r *RingBuffer) Length() int {
 }
 
 // Capacity returns the size of the underlying buffer.
+// Reads, writes and Reset don't reallocate the underlying buffer, so the
+// capacity only changes when a buffer created by NewGrowable grows, when
+// Shrink shrinks any buffer, or when Swap installs a new underlying buffer.
 func (r *RingBuffer) Capacity() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.size
 }
 
+// Cap is an alias for Capacity, matching bytes.Buffer.
+func (r *RingBuffer) Cap() int {
+	return r.Capacity()
+}
+
 // Free returns the length of available bytes to write.
 func (r *RingBuffer) Free() int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	return r.free()
+}
 
+// free is Free without locking. It must be called with r.mu held.
+func (r *RingBuffer) free() int {
 	if r.w == r.r {
 		if r.isFull {
 			return 0
@@ -214,12 +602,27 @@ func (r *RingBuffer) Free() int {
 	return r.size - r.w + r.r
 }
 
-// WriteString writes the contents of the string s to buffer, which accepts a slice of bytes.
+// AvailableContiguous returns the number of bytes that can be written
+// in one unbroken span starting at the write position.
+// Unlike Free, it does not count free space that wraps around to the start
+// of the underlying buffer, so it may be less than Free.
+func (r *RingBuffer) AvailableContiguous() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	free := r.free()
+	if c := r.size - r.w; c < free {
+		return c
+	}
+	return free
+}
+
+// WriteString writes the contents of the string s to buffer, like Write.
+// The string is copied directly into the buffer, without converting it
+// to a byte slice first.
 func (r *RingBuffer) WriteString(s string) (n int, err error) {
-	x := (*[2]uintptr)(unsafe.Pointer(&s))
-	h := [3]uintptr{x[0], x[1], x[1]}
-	buf := *(*[]byte)(unsafe.Pointer(&h))
-	return r.Write(buf)
+	n, _, err = writeData(r, s)
+	return n, err
 }
 
 // Bytes returns all available read bytes.
@@ -227,7 +630,31 @@ func (r *RingBuffer) WriteString(s string) (n int, err error) {
 func (r *RingBuffer) Bytes() []byte {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	return r.bytes()
+}
 
+// ReadAll reads all the unread bytes and returns them in a newly allocated
+// slice, like Bytes, but consuming them. It returns an empty, non-nil slice
+// if the buffer is empty.
+func (r *RingBuffer) ReadAll() []byte {
+	r.mu.Lock()
+	p := r.bytes()
+	if p == nil {
+		p = []byte{}
+	}
+	r.consumed(p)
+	r.advance(len(p))
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return p
+}
+
+// bytes is Bytes without locking. It must be called with r.mu held.
+func (r *RingBuffer) bytes() []byte {
 	if r.w == r.r {
 		if r.isFull {
 			buf := make([]byte, r.size)
@@ -259,6 +686,75 @@ func (r *RingBuffer) Bytes() []byte {
 	return buf
 }
 
+// AppendBytes appends the unread bytes to dst and returns the extended slice,
+// without changing the read pointer. The bytes are in the same order as
+// returned by Bytes. It only allocates if dst does not have enough capacity.
+func (r *RingBuffer) AppendBytes(dst []byte) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.length()
+	m := len(dst)
+	if cap(dst)-m < n {
+		buf := make([]byte, m, m+n)
+		copy(buf, dst)
+		dst = buf
+	}
+	dst = dst[:m+n]
+	r.peek(dst[m:])
+	return dst
+}
+
+// BytesInto returns the unread bytes, like Bytes, but stored in dst,
+// whose contents are replaced. It only allocates if dst does not have
+// enough capacity, so reusing the result avoids allocating on every call.
+func (r *RingBuffer) BytesInto(dst []byte) []byte {
+	return r.AppendBytes(dst[:0])
+}
+
+// CopyBytes copies up to len(dst) unread bytes into dst, without changing
+// the read pointer or allocating, and returns the number of bytes copied.
+func (r *RingBuffer) CopyBytes(dst []byte) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.peek(dst)
+}
+
+// peek copies up to len(p) unread bytes into p without advancing
+// the read pointer. It must be called with r.mu held.
+func (r *RingBuffer) peek(p []byte) int {
+	n := r.length()
+	if n > len(p) {
+		n = len(p)
+	}
+	if n == 0 {
+		return 0
+	}
+
+	if r.r+n <= r.size {
+		copy(p, r.buf[r.r:r.r+n])
+	} else {
+		c1 := r.size - r.r
+		copy(p, r.buf[r.r:r.size])
+		copy(p[c1:], r.buf[0:n-c1])
+	}
+	return n
+}
+
+// segments returns the unread data as up to two slices of the underlying
+// buffer, in order. The second slice is nil unless the data wraps around.
+// It must be called with r.mu held.
+func (r *RingBuffer) segments() (first, second []byte) {
+	n := r.length()
+	if n == 0 {
+		return nil, nil
+	}
+	if r.r+n <= r.size {
+		return r.buf[r.r : r.r+n], nil
+	}
+	return r.buf[r.r:], r.buf[:n-(r.size-r.r)]
+}
+
 // IsFull returns this ringbuffer is full.
 func (r *RingBuffer) IsFull() bool {
 	r.mu.Lock()
@@ -273,11 +769,90 @@ func (r *RingBuffer) IsEmpty() bool {
 	return !r.isFull && r.w == r.r
 }
 
-// Reset the read pointer and writer pointer to zero.
+// WouldWrap reports whether the unread data wraps around the end of the
+// underlying buffer, so that it can't be accessed as a single contiguous
+// slice, for example by Next.
+func (r *RingBuffer) WouldWrap() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r+r.length() > r.size
+}
+
+// Reset the read pointer and writer pointer to zero, discarding any
+// unread data. Goroutines blocked on the buffer are woken to re-check it:
+// blocked writes continue into the freed space, and blocked reads keep
+// waiting for new data. Reset does not reopen a closed buffer.
 func (r *RingBuffer) Reset() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.reset()
+}
+
+// Clear is the same as Reset. It discards any unread data in constant time,
+// without reallocating or zeroing the underlying buffer, so the discarded
+// bytes remain in memory until they are overwritten. Use ClearZero to
+// overwrite them.
+func (r *RingBuffer) Clear() {
+	r.Reset()
+}
+
+// ClearZero is like Clear, but also overwrites the whole underlying buffer
+// with zeros, including discarded data that was already read, so that no
+// sensitive data remains in it. It takes time proportional to the size of
+// the buffer. It does not reallocate the buffer, and does not clear copies
+// of the data made elsewhere, such as by Bytes or a pool.
+func (r *RingBuffer) ClearZero() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.buf {
+		r.buf[i] = 0
+	}
+	r.reset()
+}
+
+// ResetTo is like Reset, but also writes data into the emptied buffer, in
+// a single operation, so that concurrent readers see either the old contents
+// or data, never the buffer empty in between. It is meant for reusing a
+// buffer, such as one from a BufferPool, with fresh contents.
+// data is written as by Write: a buffer created by NewGrowable grows to fit
+// it, and if it still doesn't fit, as much as fits is written and
+// ErrShortWrite returned. It returns the number of bytes of data written.
+// It returns ErrClosed, or ErrFull for a buffer created with
+// WithStrictWriter that can't hold all of data, without resetting the
+// buffer.
+func (r *RingBuffer) ResetTo(data []byte) (n int, err error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return 0, ErrClosed
+	}
+	if r.strict && len(data) > r.size && !r.canGrow(len(data)) {
+		r.mu.Unlock()
+		return 0, ErrFull
+	}
+	r.reset()
+	if len(data) > 0 {
+		n, err = writeChecked(r, data)
+	}
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
+
+// reset is Reset without locking. It must be called with r.mu held.
+func (r *RingBuffer) reset() {
+	r.pos += int64(r.length())
 	r.r = 0
 	r.w = 0
+	r.replay = 0
+	r.pinned = 0
 	r.isFull = false
+	r.msgs = nil
+	r.changed()
+	// Discarding data is not a read that drains the buffer.
+	r.emptyDue = false
 }
@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"math/bits"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -18,6 +20,10 @@ func TestRingBuffer_interface(t *testing.T) {
 	var _ io.Reader = rb
 	var _ io.ByteReader = rb
 	var _ io.ByteWriter = rb
+	var _ io.RuneReader = rb
+	var _ io.ByteScanner = rb
+	var _ io.RuneScanner = rb
+	var _ io.ReadWriteCloser = rb.ReadWriteCloser()
 }
 
 func TestRingBuffer_Write(t *testing.T) {
@@ -121,8 +127,14 @@ func TestRingBuffer_Write(t *testing.T) {
 	// reset this ringbuffer and set a long slice
 	rb.Reset()
 	n, err = rb.Write([]byte(strings.Repeat("abcd", 20)))
-	if err == nil {
-		t.Fatalf("expect ErrTooManyDataToWrite but got nil")
+	if !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+	if errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrShortWrite to be distinct from ErrFull")
+	}
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite to wrap io.ErrShortWrite")
 	}
 	if n != 64 {
 		t.Fatalf("expect write 64 bytes but got %d", n)
@@ -481,3 +493,718 @@ func BenchmarkRingBuffer_AsyncWrite(b *testing.B) {
 		}
 	}
 }
+
+func TestRingBuffer_AvailableContiguous(t *testing.T) {
+	rb := New(8)
+
+	if rb.AvailableContiguous() != 8 {
+		t.Fatalf("expect 8 contiguous bytes but got %d. r.w=%d, r.r=%d", rb.AvailableContiguous(), rb.w, rb.r)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := rb.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	// free space wraps: 2 bytes at the end, 4 at the start
+	if rb.Free() != 6 {
+		t.Fatalf("expect free 6 bytes but got %d. r.w=%d, r.r=%d", rb.Free(), rb.w, rb.r)
+	}
+	if rb.AvailableContiguous() != 2 {
+		t.Fatalf("expect 2 contiguous bytes but got %d. r.w=%d, r.r=%d", rb.AvailableContiguous(), rb.w, rb.r)
+	}
+
+	if _, err := rb.Write([]byte("ghi")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.AvailableContiguous() != 3 {
+		t.Fatalf("expect 3 contiguous bytes but got %d. r.w=%d, r.r=%d", rb.AvailableContiguous(), rb.w, rb.r)
+	}
+
+	if _, err := rb.Write([]byte("jkl")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.AvailableContiguous() != 0 {
+		t.Fatalf("expect 0 contiguous bytes but got %d. r.w=%d, r.r=%d", rb.AvailableContiguous(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_WriteStringOversized(t *testing.T) {
+	data := strings.Repeat("abcdefgh", 4)
+	for _, tc := range []struct {
+		name string
+		new  func() *RingBuffer
+	}{
+		{"Empty", func() *RingBuffer { return New(8) }},
+		{"Wrapped", func() *RingBuffer {
+			rb := New(8)
+			_, _ = rb.Write([]byte("xxxxxx"))
+			_, _ = rb.Read(make([]byte, 5))
+			return rb
+		}},
+		{"Strict", func() *RingBuffer { return New(8, WithStrictWriter()) }},
+		{"Growable", func() *RingBuffer { return NewGrowable(4, 16) }},
+		{"Overwrite", func() *RingBuffer { return New(8, WithOverwrite()) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rbBytes, rbString := tc.new(), tc.new()
+			nBytes, errBytes := rbBytes.Write([]byte(data))
+			nString, errString := rbString.WriteString(data)
+
+			if nString != nBytes || errString != errBytes {
+				t.Fatalf("expect WriteString to return %d, %v like Write but got %d, %v", nBytes, errBytes, nString, errString)
+			}
+			if !rbString.ContentEquals(rbBytes) {
+				t.Fatalf("expect WriteString to write %s like Write but got %s", rbBytes.Bytes(), rbString.Bytes())
+			}
+			if rbString.Capacity() != rbBytes.Capacity() {
+				t.Fatalf("expect capacity %d but got %d", rbBytes.Capacity(), rbString.Capacity())
+			}
+		})
+	}
+
+	// a non-strict write fills the buffer and reports a short write
+	rb := New(8)
+	n, err := rb.WriteString(data)
+	if !errors.Is(err, ErrShortWrite) {
+		t.Fatalf("expect ErrShortWrite but got %v", err)
+	}
+	if n != 8 {
+		t.Fatalf("expect write 8 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcdefgh")) {
+		t.Fatalf("expect abcdefgh but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_WriteReportFull(t *testing.T) {
+	rb := New(4)
+
+	n, full, err := rb.WriteReportFull([]byte("ab"))
+	if err != nil || n != 2 || full {
+		t.Fatalf("expect write 2 bytes without filling but got %d, %t, %v", n, full, err)
+	}
+	n, full, err = rb.WriteReportFull([]byte("cdef"))
+	if !errors.Is(err, ErrShortWrite) || n != 2 || !full {
+		t.Fatalf("expect a short write of 2 bytes that fills but got %d, %t, %v", n, full, err)
+	}
+
+	// already full
+	n, full, err = rb.WriteReportFull([]byte("g"))
+	if !errors.Is(err, ErrFull) || n != 0 || full {
+		t.Fatalf("expect ErrFull without filling but got %d, %t, %v", n, full, err)
+	}
+
+	if _, err := rb.Read(make([]byte, 1)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	n, full, err = rb.WriteReportFull([]byte("g"))
+	if err != nil || n != 1 || !full {
+		t.Fatalf("expect write 1 byte that fills but got %d, %t, %v", n, full, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("bcdg")) {
+		t.Fatalf("expect bcdg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_ReadAll(t *testing.T) {
+	rb := New(8)
+
+	if p := rb.ReadAll(); p == nil || len(p) != 0 {
+		t.Fatalf("expect an empty non-nil slice but got %v", p)
+	}
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if p := rb.ReadAll(); !bytes.Equal(p, []byte("efghij")) {
+		t.Fatalf("expect efghij but got %s", p)
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_BytesInto(t *testing.T) {
+	rb := New(8)
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	got := rb.BytesInto([]byte("xyz"))
+	if !bytes.Equal(got, []byte("efghij")) {
+		t.Fatalf("expect efghij but got %s", got)
+	}
+
+	// reuses the capacity of dst
+	dst := make([]byte, 2, 16)
+	got = rb.BytesInto(dst)
+	if &got[0] != &dst[0] {
+		t.Fatalf("expect dst to be reused")
+	}
+	if !bytes.Equal(got, []byte("efghij")) {
+		t.Fatalf("expect efghij but got %s", got)
+	}
+
+	rb.Reset()
+	if got = rb.BytesInto(got); len(got) != 0 {
+		t.Fatalf("expect no bytes but got %s", got)
+	}
+}
+
+func TestRingBuffer_CopyBytes(t *testing.T) {
+	rb := New(8)
+	dst := make([]byte, 4)
+
+	if n := rb.CopyBytes(dst); n != 0 {
+		t.Fatalf("expect copy 0 bytes but got %d", n)
+	}
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if n := rb.CopyBytes(dst); n != 4 || !bytes.Equal(dst, []byte("efgh")) {
+		t.Fatalf("expect efgh but got %s", dst[:n])
+	}
+	dst = make([]byte, 8)
+	if n := rb.CopyBytes(dst); n != 6 || !bytes.Equal(dst[:n], []byte("efghij")) {
+		t.Fatalf("expect efghij but got %s", dst[:n])
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_AppendBytes(t *testing.T) {
+	rb := New(8)
+
+	if got := rb.AppendBytes([]byte("x")); !bytes.Equal(got, []byte("x")) {
+		t.Fatalf("expect x but got %s", got)
+	}
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	got := rb.AppendBytes([]byte("x"))
+	if !bytes.Equal(got, append([]byte("x"), rb.Bytes()...)) {
+		t.Fatalf("expect x followed by %s but got %s", rb.Bytes(), got)
+	}
+
+	// reuses the capacity of dst
+	dst := make([]byte, 0, 16)
+	got = rb.AppendBytes(dst)
+	if &got[0] != &dst[:1][0] {
+		t.Fatalf("expect dst to be reused")
+	}
+	if !bytes.Equal(got, []byte("efghij")) {
+		t.Fatalf("expect efghij but got %s", got)
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_ZeroSize(t *testing.T) {
+	rb := New(0)
+
+	if !rb.IsEmpty() || rb.IsFull() {
+		t.Fatalf("expect an empty buffer that is not full. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+	if rb.Length() != 0 || rb.Free() != 0 || rb.Capacity() != 0 {
+		t.Fatalf("expect len, free and capacity 0 but got %d, %d, %d", rb.Length(), rb.Free(), rb.Capacity())
+	}
+
+	if n, err := rb.Write([]byte("a")); !errors.Is(err, ErrFull) || n != 0 {
+		t.Fatalf("expect ErrFull but got %d, %v", n, err)
+	}
+	if n, err := rb.WriteString("a"); !errors.Is(err, ErrFull) || n != 0 {
+		t.Fatalf("expect ErrFull but got %d, %v", n, err)
+	}
+	if err := rb.WriteByte('a'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if err := rb.WriteByteFront('a'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if _, err := rb.WriteRune('a'); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if _, err := rb.BlockingWrite([]byte("a")); !errors.Is(err, ErrExceedsCapacity) {
+		t.Fatalf("expect ErrExceedsCapacity but got %v", err)
+	}
+	if n, err := rb.Write(nil); err != nil || n != 0 {
+		t.Fatalf("expect an empty write to succeed but got %d, %v", n, err)
+	}
+
+	if _, err := rb.Read(make([]byte, 1)); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.ReadByte(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.ReadByteBack(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if p := rb.Bytes(); len(p) != 0 {
+		t.Fatalf("expect no bytes but got %s", p)
+	}
+	if rb.IsFull() {
+		t.Fatalf("expect IsFull is false but got true. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+
+	rb.Reset()
+	rb.Compact()
+	if err := rb.checkInvariants(); err != nil {
+		t.Fatalf("expect no error but got %v", err)
+	}
+}
+
+func TestNew_InvalidSize(t *testing.T) {
+	sizes := []int{-1, MaxSize + 1}
+	if bits.UintSize == 64 {
+		// within MaxSize, but more than the runtime can allocate
+		sizes = append(sizes, MaxSize)
+	}
+	for _, size := range sizes {
+		func() {
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.HasPrefix(msg, "ringbuffer: ") {
+					t.Fatalf("expect a ringbuffer panic for size %d but got %q", size, msg)
+				}
+			}()
+			New(size)
+		}()
+	}
+}
+
+func TestRingBuffer_BytesFull(t *testing.T) {
+	const size = 8
+	for pos := 0; pos < size; pos++ {
+		rb := New(size)
+
+		// move r and w to pos, writing past the wrap on the way
+		if _, err := rb.Write(make([]byte, size-1)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if _, err := rb.Read(make([]byte, size-1)); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if _, err := rb.Write(make([]byte, pos+1)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if _, err := rb.Read(make([]byte, pos+1)); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if rb.r != pos || rb.w != pos {
+			t.Fatalf("expect r and w at %d. r.w=%d, r.r=%d", pos, rb.w, rb.r)
+		}
+
+		// refill to full in two writes
+		if _, err := rb.Write([]byte("abc")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if _, err := rb.Write([]byte("defgh")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if !rb.IsFull() {
+			t.Fatalf("expect IsFull is true but got false. r.w=%d, r.r=%d", rb.w, rb.r)
+		}
+		if got := rb.Bytes(); !bytes.Equal(got, []byte("abcdefgh")) {
+			t.Fatalf("expect abcdefgh but got %s. r.w=%d, r.r=%d", got, rb.w, rb.r)
+		}
+
+		// read some and refill to full again
+		if _, err := rb.Read(make([]byte, 3)); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if _, err := rb.Write([]byte("ijk")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if got := rb.Bytes(); !bytes.Equal(got, []byte("defghijk")) {
+			t.Fatalf("expect defghijk but got %s. r.w=%d, r.r=%d", got, rb.w, rb.r)
+		}
+	}
+}
+
+func TestRingBuffer_LengthAndBytes(t *testing.T) {
+	rb := New(8)
+
+	n, buf := rb.LengthAndBytes()
+	if n != 0 || buf != nil {
+		t.Fatalf("expect 0 and nil but got %d and %q", n, buf)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if rb.Len() != rb.Length() {
+		t.Fatalf("expect Len %d but got %d", rb.Length(), rb.Len())
+	}
+
+	// concurrent writers never make the pair inconsistent
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			_, _ = rb.ReadByte()
+			_ = rb.WriteByte('x')
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		n, buf := rb.LengthAndBytes()
+		if n != len(buf) {
+			t.Fatalf("expect len %d to match %d bytes", n, len(buf))
+		}
+	}
+	<-done
+}
+
+func TestRingBuffer_LengthFree(t *testing.T) {
+	rb := New(8)
+	if l, f := rb.LengthFree(); l != 0 || f != 8 {
+		t.Fatalf("expect 0 and 8 but got %d and %d", l, f)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			_, _ = rb.Write([]byte("abc"))
+			_, _ = rb.Read(make([]byte, 2))
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		if l, f := rb.LengthFree(); l+f != rb.Capacity() {
+			t.Fatalf("expect length %d and free %d to add up to %d", l, f, rb.Capacity())
+		}
+	}
+	<-done
+}
+
+func TestRingBuffer_Capacity(t *testing.T) {
+	rb := New(8)
+
+	check := func() {
+		t.Helper()
+		if rb.Capacity() != 8 {
+			t.Fatalf("expect capacity 8 but got %d", rb.Capacity())
+		}
+		if rb.Cap() != rb.Capacity() {
+			t.Fatalf("expect Cap %d but got %d", rb.Capacity(), rb.Cap())
+		}
+	}
+
+	check()
+	if _, err := rb.Write([]byte(strings.Repeat("a", 10))); err == nil {
+		t.Fatalf("expect ErrShortWrite but got nil")
+	}
+	check()
+	if _, err := rb.Read(make([]byte, 5)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	check()
+	rb.Reset()
+	check()
+
+	// Shrink changes the capacity of any buffer
+	rb.Shrink(2)
+	if rb.Capacity() != 2 || rb.Cap() != 2 {
+		t.Fatalf("expect capacity 2 but got %d and Cap %d", rb.Capacity(), rb.Cap())
+	}
+}
+
+func BenchmarkRead_Contiguous(b *testing.B) {
+	rb := New(1024)
+	data := []byte(strings.Repeat("a", 512))
+	buf := make([]byte, 512)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// keep the data at the start of the buffer so it never wraps
+		rb.Reset()
+		if _, err := rb.Write(data); err != nil {
+			b.Fatalf("write failed: %v", err)
+		}
+		if _, err := rb.Read(buf); err != nil {
+			b.Fatalf("read failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkRead_Wrapped(b *testing.B) {
+	rb := New(1024)
+	data := []byte(strings.Repeat("a", 512))
+	buf := make([]byte, 512)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// start the data 256 bytes before the end so it always wraps
+		rb.Reset()
+		rb.r, rb.w = 768, 768
+		if _, err := rb.Write(data); err != nil {
+			b.Fatalf("write failed: %v", err)
+		}
+		if _, err := rb.Read(buf); err != nil {
+			b.Fatalf("read failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkRingBuffer_WriteString(b *testing.B) {
+	for _, size := range []int{8, 64, 512} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			rb := New(1024)
+			s := strings.Repeat("a", size)
+
+			b.ReportAllocs()
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				rb.Reset()
+				if _, err := rb.WriteString(s); err != nil {
+					b.Fatalf("write failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRingBuffer_WouldWrap(t *testing.T) {
+	rb := New(8)
+
+	if rb.WouldWrap() {
+		t.Fatalf("expect WouldWrap is false but got true")
+	}
+	if _, err := rb.Write([]byte(strings.Repeat("a", 8))); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	// full from offset zero
+	if rb.WouldWrap() {
+		t.Fatalf("expect WouldWrap is false but got true. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if rb.WouldWrap() {
+		t.Fatalf("expect WouldWrap is false but got true. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+	if _, err := rb.Write([]byte("b")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !rb.WouldWrap() {
+		t.Fatalf("expect WouldWrap is true but got false. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+	// full from a non-zero offset
+	if _, err := rb.Write([]byte(strings.Repeat("c", 5))); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !rb.WouldWrap() {
+		t.Fatalf("expect WouldWrap is true but got false. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+}
+
+func BenchmarkRingBuffer_Bytes(b *testing.B) {
+	rb := New(1024)
+	if _, err := rb.Write([]byte(strings.Repeat("a", 512))); err != nil {
+		b.Fatalf("write failed: %v", err)
+	}
+
+	b.Run("Bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = rb.Bytes()
+		}
+	})
+	b.Run("BytesInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			buf = rb.BytesInto(buf)
+		}
+	})
+}
+
+func TestRingBuffer_ResetTo(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	n, err := rb.ResetTo([]byte("xyz"))
+	if err != nil || n != 3 {
+		t.Fatalf("expect 3 bytes but got %d, %v", n, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("xyz")) || rb.r != 0 || rb.w != 3 {
+		t.Fatalf("expect xyz but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	n, err = rb.ResetTo([]byte("0123456789"))
+	if !errors.Is(err, ErrShortWrite) || n != 8 {
+		t.Fatalf("expect 8 bytes and ErrShortWrite but got %d, %v", n, err)
+	}
+	if !rb.IsFull() || !bytes.Equal(rb.Bytes(), []byte("01234567")) {
+		t.Fatalf("expect a full buffer of 01234567 but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	if n, err = rb.ResetTo(nil); err != nil || n != 0 || !rb.IsEmpty() {
+		t.Fatalf("expect an empty buffer but got %d, %v, %d bytes", n, err, rb.Length())
+	}
+
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := rb.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if _, err = rb.ResetTo([]byte("x")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("ab")) {
+		t.Fatalf("expect ab but got %s", rb.Bytes())
+	}
+}
+
+func TestRingBuffer_ResetToWriteOptions(t *testing.T) {
+	// a growable buffer grows as Write would
+	rb := NewGrowable(4, 64)
+	n, err := rb.ResetTo([]byte("0123456789"))
+	if err != nil || n != 10 {
+		t.Fatalf("expect 10 bytes but got %d, %v", n, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("0123456789")) {
+		t.Fatalf("expect 0123456789 but got %s", rb.Bytes())
+	}
+
+	// a strict buffer installs all of data or nothing
+	rb = New(4, WithStrictWriter())
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.ResetTo([]byte("01234")); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("ab")) {
+		t.Fatalf("expect ab but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if n, err = rb.ResetTo([]byte("0123")); err != nil || n != 4 {
+		t.Fatalf("expect 4 bytes but got %d, %v", n, err)
+	}
+	// nor when a grow policy refuses to grow for data
+	refuse := func(current, needed int) (int, bool) { return 0, false }
+	rb = NewGrowable(4, 64, WithStrictWriter(), WithGrowPolicy(refuse))
+	if _, err := rb.Write([]byte("abc")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.ResetTo([]byte("0123456")); !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abc")) {
+		t.Fatalf("expect abc but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_Clear(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := rb.buf
+
+	rb.Clear()
+	if !rb.IsEmpty() || rb.r != 0 || rb.w != 0 {
+		t.Fatalf("expect an empty buffer but got %d bytes. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+	// Clear neither reallocates nor zeroes the buffer
+	if &rb.buf[0] != &buf[0] || !bytes.Equal(rb.buf, []byte("ijcdefgh")) {
+		t.Fatalf("expect the buffer to be kept but got %q", rb.buf)
+	}
+
+	if _, err := rb.Write([]byte("xyz")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	rb.ClearZero()
+	if !rb.IsEmpty() || rb.r != 0 || rb.w != 0 {
+		t.Fatalf("expect an empty buffer but got %d bytes. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+	if &rb.buf[0] != &buf[0] || !bytes.Equal(rb.buf, make([]byte, 8)) {
+		t.Fatalf("expect the buffer to be zeroed but got %q", rb.buf)
+	}
+}
+
+func TestRingBuffer_TryByte(t *testing.T) {
+	rb := New(2)
+
+	if _, ok := rb.TryReadByte(); ok {
+		t.Fatalf("expect TryReadByte to fail on an empty buffer")
+	}
+	if !rb.TryWriteByte('a') || !rb.TryWriteByte('b') {
+		t.Fatalf("expect TryWriteByte to succeed. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+	if rb.TryWriteByte('c') {
+		t.Fatalf("expect TryWriteByte to fail on a full buffer")
+	}
+	if b, ok := rb.TryReadByte(); !ok || b != 'a' {
+		t.Fatalf("expect a but got %c, %v", b, ok)
+	}
+
+	_ = rb.Close()
+	if rb.TryWriteByte('c') {
+		t.Fatalf("expect TryWriteByte to fail on a closed buffer")
+	}
+	if b, ok := rb.TryReadByte(); !ok || b != 'b' {
+		t.Fatalf("expect b but got %c, %v", b, ok)
+	}
+	if _, ok := rb.TryReadByte(); ok {
+		t.Fatalf("expect TryReadByte to fail on a drained buffer")
+	}
+}
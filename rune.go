@@ -0,0 +1,87 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// ReadRune reads a single UTF-8 encoded rune and returns the rune and its
+// size in bytes. A rune may wrap around the end of the underlying buffer.
+// If the encoded rune is invalid, it consumes one byte and returns
+// (utf8.RuneError, 1), like bufio.Reader.ReadRune.
+// If the unread data ends with an incomplete rune, it returns ErrEmpty
+// without consuming anything, unless the buffer is closed, in which case
+// the incomplete rune is treated as invalid.
+func (r *RingBuffer) ReadRune() (ch rune, size int, err error) {
+	r.mu.Lock()
+	ch, size, err = r.readRune()
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return ch, size, err
+}
+
+// readRune is ReadRune without locking. It must be called with r.mu held.
+func (r *RingBuffer) readRune() (ch rune, size int, err error) {
+	if r.w == r.r && !r.isFull {
+		return 0, 0, r.emptyErr()
+	}
+
+	var buf [utf8.UTFMax]byte
+	n := r.peek(buf[:])
+	if !utf8.FullRune(buf[:n]) && !r.closed {
+		return 0, 0, ErrEmpty
+	}
+
+	ch, size = rune(buf[0]), 1
+	if ch >= utf8.RuneSelf {
+		ch, size = utf8.DecodeRune(buf[:n])
+	}
+	r.consumed(buf[:size])
+	r.advance(size)
+	r.runeLen = size
+	return ch, size, nil
+}
+
+// UnreadRune unreads the last rune returned by ReadRune, so that it is
+// returned by the next read, implementing io.RuneScanner.
+// It returns bufio.ErrInvalidUnreadRune unless the last operation on the
+// buffer was a successful ReadRune.
+func (r *RingBuffer) UnreadRune() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.runeLen == 0 {
+		return bufio.ErrInvalidUnreadRune
+	}
+	r.rewind(r.runeLen)
+	return nil
+}
+
+// WriteRune writes the UTF-8 encoding of ch to the buffer and returns the
+// number of bytes written. It writes the whole encoding or nothing,
+// returning ErrFull if it does not fit.
+func (r *RingBuffer) WriteRune(ch rune) (int, error) {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], ch)
+
+	r.mu.Lock()
+	err := r.writeAll(buf[:n])
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bufio"
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRingBuffer_Rune(t *testing.T) {
+	rb := New(8)
+
+	for _, ch := range "aé世" {
+		if _, err := rb.WriteRune(ch); err != nil {
+			t.Fatalf("WriteRune failed: %v", err)
+		}
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	// a 4-byte rune doesn't fit in the 2 free bytes
+	n, err := rb.WriteRune('😀')
+	if !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect write 0 bytes but got %d", n)
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	for _, want := range "aé世" {
+		ch, size, err := rb.ReadRune()
+		if err != nil {
+			t.Fatalf("ReadRune failed: %v", err)
+		}
+		if ch != want || size != utf8.RuneLen(want) {
+			t.Fatalf("expect %c (%d bytes) but got %c (%d bytes)", want, utf8.RuneLen(want), ch, size)
+		}
+	}
+	if _, _, err := rb.ReadRune(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	// invalid encoding
+	if _, err := rb.Write([]byte{0xff, 'b'}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	ch, size, err := rb.ReadRune()
+	if err != nil {
+		t.Fatalf("ReadRune failed: %v", err)
+	}
+	if ch != utf8.RuneError || size != 1 {
+		t.Fatalf("expect RuneError (1 byte) but got %c (%d bytes)", ch, size)
+	}
+	if ch, _, _ := rb.ReadRune(); ch != 'b' {
+		t.Fatalf("expect b but got %c", ch)
+	}
+}
+
+func TestRingBuffer_ReadRuneWrapped(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		pad   int // bytes written and read before the rune
+		runes string
+	}{
+		{"2-byte rune split 1+1", 7, "é"},
+		{"3-byte rune split 1+2", 7, "世"},
+		{"3-byte rune split 2+1", 6, "世"},
+		{"4-byte rune split 1+3", 7, "😀"},
+		{"4-byte rune split 3+1", 5, "😀"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rb := New(8)
+			if _, err := rb.Write(make([]byte, tc.pad)); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if _, err := rb.Read(make([]byte, tc.pad)); err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			if _, err := rb.WriteString(tc.runes); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+
+			want, wantSize := utf8.DecodeRuneInString(tc.runes)
+			ch, size, err := rb.ReadRune()
+			if err != nil {
+				t.Fatalf("ReadRune failed: %v", err)
+			}
+			if ch != want || size != wantSize {
+				t.Fatalf("expect %c (%d bytes) but got %c (%d bytes)", want, wantSize, ch, size)
+			}
+			if !rb.IsEmpty() {
+				t.Fatalf("expect IsEmpty is true but got false")
+			}
+		})
+	}
+}
+
+func TestRingBuffer_ReadRuneIncomplete(t *testing.T) {
+	rb := New(8)
+
+	// the first two bytes of a 3-byte rune, wrapped around the end
+	if _, err := rb.Write(make([]byte, 7)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 7)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	enc := []byte("世")
+	if _, err := rb.Write(enc[:2]); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if _, _, err := rb.ReadRune(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if rb.Length() != 2 {
+		t.Fatalf("expect len 2 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	if err := rb.WriteByte(enc[2]); err != nil {
+		t.Fatalf("WriteByte failed: %v", err)
+	}
+	ch, size, err := rb.ReadRune()
+	if err != nil {
+		t.Fatalf("ReadRune failed: %v", err)
+	}
+	if ch != '世' || size != 3 {
+		t.Fatalf("expect 世 (3 bytes) but got %c (%d bytes)", ch, size)
+	}
+
+	// an incomplete rune can't complete once closed
+	if _, err := rb.Write(enc[:1]); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = rb.Close()
+	ch, size, err = rb.ReadRune()
+	if err != nil {
+		t.Fatalf("ReadRune failed: %v", err)
+	}
+	if ch != utf8.RuneError || size != 1 {
+		t.Fatalf("expect RuneError (1 byte) but got %c (%d bytes)", ch, size)
+	}
+}
+
+func TestRingBuffer_UnreadRune(t *testing.T) {
+	rb := New(8)
+
+	if err := rb.UnreadRune(); !errors.Is(err, bufio.ErrInvalidUnreadRune) {
+		t.Fatalf("expect bufio.ErrInvalidUnreadRune but got %v", err)
+	}
+
+	// the 3-byte rune wraps around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.WriteString("世a"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	ch, size, err := rb.ReadRune()
+	if err != nil || ch != '世' || size != 3 {
+		t.Fatalf("expect 世 of size 3 but got %c, %d, %v", ch, size, err)
+	}
+	if err := rb.UnreadRune(); err != nil {
+		t.Fatalf("UnreadRune failed: %v", err)
+	}
+	if rb.Length() != 4 {
+		t.Fatalf("expect len 4 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+	if ch, _, _ = rb.ReadRune(); ch != '世' {
+		t.Fatalf("expect 世 but got %c", ch)
+	}
+
+	// only after ReadRune
+	if err := rb.UnreadRune(); err != nil {
+		t.Fatalf("UnreadRune failed: %v", err)
+	}
+	if err := rb.UnreadRune(); !errors.Is(err, bufio.ErrInvalidUnreadRune) {
+		t.Fatalf("expect bufio.ErrInvalidUnreadRune but got %v", err)
+	}
+	if _, err := rb.ReadByte(); err != nil {
+		t.Fatalf("ReadByte failed: %v", err)
+	}
+	if err := rb.UnreadRune(); !errors.Is(err, bufio.ErrInvalidUnreadRune) {
+		t.Fatalf("expect bufio.ErrInvalidUnreadRune but got %v", err)
+	}
+	if ch, _, _ = rb.ReadRune(); ch != utf8.RuneError {
+		t.Fatalf("expect utf8.RuneError but got %c", ch)
+	}
+	if _, err := rb.WriteString("b"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := rb.UnreadRune(); !errors.Is(err, bufio.ErrInvalidUnreadRune) {
+		t.Fatalf("expect bufio.ErrInvalidUnreadRune but got %v", err)
+	}
+}
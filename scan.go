@@ -0,0 +1,53 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "bufio"
+
+// Scan reads the next token from the buffered data using split, such as
+// bufio.ScanLines or bufio.ScanWords, and consumes the number of bytes split
+// advances by. split is called with atEOF set once the buffer is closed.
+// If split needs more data than is buffered, Scan consumes nothing and
+// returns ErrEmpty, or ErrClosed if the buffer is closed. Errors from split
+// are returned without consuming anything, except bufio.ErrFinalToken,
+// which is treated as a successful scan.
+// The token does not alias the buffer, so it may be retained.
+func (r *RingBuffer) Scan(split bufio.SplitFunc) (token []byte, err error) {
+	r.mu.Lock()
+	token, err = r.scan(split)
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return token, err
+}
+
+// scan is Scan without locking. It must be called with r.mu held.
+func (r *RingBuffer) scan(split bufio.SplitFunc) ([]byte, error) {
+	if r.length() == 0 {
+		return nil, r.emptyErr()
+	}
+
+	data := r.bytes()
+	advance, token, err := split(data, r.closed)
+	if err != nil && err != bufio.ErrFinalToken {
+		return nil, err
+	}
+	if advance < 0 {
+		return nil, bufio.ErrNegativeAdvance
+	}
+	if advance > len(data) {
+		return nil, bufio.ErrAdvanceTooFar
+	}
+	if advance == 0 && token == nil {
+		return nil, r.emptyErr()
+	}
+
+	r.consumed(data[:advance])
+	r.advance(advance)
+	return token, nil
+}
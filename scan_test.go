@@ -0,0 +1,94 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bufio"
+	"errors"
+	"testing"
+)
+
+func TestRingBuffer_Scan(t *testing.T) {
+	rb := New(16)
+
+	if _, err := rb.Scan(bufio.ScanLines); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	// wrap the data around the end of the buffer
+	if _, err := rb.Write(make([]byte, 12)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 12)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.WriteString("one\r\ntwo\nthr"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	for _, want := range []string{"one", "two"} {
+		token, err := rb.Scan(bufio.ScanLines)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if string(token) != want {
+			t.Fatalf("expect %s but got %s", want, token)
+		}
+	}
+
+	// an incomplete line is not consumed
+	if _, err := rb.Scan(bufio.ScanLines); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if rb.Length() != 3 {
+		t.Fatalf("expect len 3 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	// the final line is returned once the buffer is closed
+	if _, err := rb.WriteString("ee four"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if token, err := rb.Scan(bufio.ScanWords); err != nil || string(token) != "three" {
+		t.Fatalf("expect three but got %s, %v", token, err)
+	}
+	_ = rb.Close()
+	if token, err := rb.Scan(bufio.ScanLines); err != nil || string(token) != "four" {
+		t.Fatalf("expect four but got %s, %v", token, err)
+	}
+	if _, err := rb.Scan(bufio.ScanLines); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_ScanError(t *testing.T) {
+	rb := New(16)
+	if _, err := rb.WriteString("abc"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	errSplit := errors.New("split failed")
+	_, err := rb.Scan(func(data []byte, atEOF bool) (int, []byte, error) {
+		return 1, nil, errSplit
+	})
+	if !errors.Is(err, errSplit) {
+		t.Fatalf("expect errSplit but got %v", err)
+	}
+	_, err = rb.Scan(func(data []byte, atEOF bool) (int, []byte, error) {
+		return len(data) + 1, nil, nil
+	})
+	if !errors.Is(err, bufio.ErrAdvanceTooFar) {
+		t.Fatalf("expect bufio.ErrAdvanceTooFar but got %v", err)
+	}
+	if rb.Length() != 3 {
+		t.Fatalf("expect len 3 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	token, err := rb.Scan(func(data []byte, atEOF bool) (int, []byte, error) {
+		return 1, data[:1], bufio.ErrFinalToken
+	})
+	if err != nil || string(token) != "a" {
+		t.Fatalf("expect a but got %s, %v", token, err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"errors"
+	"io"
+)
+
+var errWhence = errors.New("ringbuffer: invalid whence")
+
+// Seek implements io.Seeker over the bytes resident in the buffer.
+// Offsets are stream offsets: the offset of a byte is the number of bytes
+// read, or skipped, before it since the buffer was created, so the current
+// offset, returned by Seek(0, io.SeekCurrent), only changes when the read
+// position does. io.SeekEnd is relative to the end of the unread data.
+// Seek moves forward by skipping unread bytes as if they had been read, and
+// back over bytes that were read but not yet overwritten, as Rewind does.
+// It returns ErrOutOfRange, without moving, if the new offset would be
+// before the oldest read byte still in the buffer or past the end of the
+// unread data. On success it returns the new offset.
+//
+// Concurrent writes append data after the end used by io.SeekEnd, and may
+// overwrite read bytes, which limits how far back a later Seek can go, but
+// neither happens while Seek holds the buffer's lock.
+func (r *RingBuffer) Seek(offset int64, whence int) (int64, error) {
+	r.mu.Lock()
+
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.pos + offset
+	case io.SeekEnd:
+		abs = r.pos + int64(r.length()) + offset
+	default:
+		r.mu.Unlock()
+		return 0, errWhence
+	}
+	if abs < r.pos-int64(r.replay) || abs > r.pos+int64(r.length()) {
+		r.mu.Unlock()
+		return 0, ErrOutOfRange
+	}
+
+	if abs < r.pos {
+		r.rewind(int(r.pos - abs))
+	} else {
+		r.advance(int(abs - r.pos))
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return abs, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_Seek(t *testing.T) {
+	rb := New(8)
+	var _ io.Seeker = rb
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("xxxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("abcdefg")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// 6 bytes have been read, and the last x is still buffered
+	pos, err := rb.Seek(0, io.SeekCurrent)
+	if err != nil || pos != 6 {
+		t.Fatalf("expect offset 6 but got %d, %v", pos, err)
+	}
+
+	if pos, err = rb.Seek(8, io.SeekStart); err != nil || pos != 8 {
+		t.Fatalf("expect offset 8 but got %d, %v", pos, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cdefg")) {
+		t.Fatalf("expect cdefg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	if pos, err = rb.Seek(1, io.SeekCurrent); err != nil || pos != 9 {
+		t.Fatalf("expect offset 9 but got %d, %v", pos, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("defg")) {
+		t.Fatalf("expect defg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	if pos, err = rb.Seek(-1, io.SeekEnd); err != nil || pos != 12 {
+		t.Fatalf("expect offset 12 but got %d, %v", pos, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("g")) {
+		t.Fatalf("expect g but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// seeking back returns to bytes already read
+	if pos, err = rb.Seek(-4, io.SeekCurrent); err != nil || pos != 8 {
+		t.Fatalf("expect offset 8 but got %d, %v", pos, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cdefg")) {
+		t.Fatalf("expect cdefg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if pos, err = rb.Seek(5, io.SeekStart); err != nil || pos != 5 {
+		t.Fatalf("expect offset 5 but got %d, %v", pos, err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("xabcdefg")) {
+		t.Fatalf("expect xabcdefg but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// out of range seeks leave the buffer untouched
+	if _, err := rb.Seek(-1, io.SeekCurrent); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	if _, err := rb.Seek(14, io.SeekStart); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	if _, err := rb.Seek(0, 42); err == nil {
+		t.Fatalf("expect an error but got nil")
+	}
+	if rb.Length() != 8 {
+		t.Fatalf("expect len 8 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	if pos, err = rb.Seek(0, io.SeekEnd); err != nil || pos != 13 {
+		t.Fatalf("expect offset 13 but got %d, %v", pos, err)
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false")
+	}
+}
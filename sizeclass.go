@@ -0,0 +1,30 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "math/bits"
+
+// RoundSize rounds size up to the next power of two.
+// Sizes that are not positive, or too large to round, are returned as is.
+// Rounding the sizes passed to BufferPool.Get means buffers requested with
+// similar sizes share a pool, so that they are more likely to be reused.
+func RoundSize(size int) int {
+	if size <= 0 {
+		return size
+	}
+	shift := bits.Len(uint(size - 1))
+	if shift >= bits.UintSize-1 {
+		return size
+	}
+	return 1 << shift
+}
+
+// WithSizeClasses rounds the size passed to New up with RoundSize,
+// so Capacity may be larger than requested.
+func WithSizeClasses() Option {
+	return func(r *RingBuffer) {
+		r.size = RoundSize(r.size)
+	}
+}
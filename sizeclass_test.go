@@ -0,0 +1,46 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRoundSize(t *testing.T) {
+	for _, tc := range []struct {
+		size, want int
+	}{
+		{-1, -1},
+		{0, 0},
+		{1, 1},
+		{2, 2},
+		{3, 4},
+		{1000, 1024},
+		{1024, 1024},
+		{1025, 2048},
+		{math.MaxInt, math.MaxInt},
+	} {
+		if got := RoundSize(tc.size); got != tc.want {
+			t.Fatalf("expect RoundSize(%d) to be %d but got %d", tc.size, tc.want, got)
+		}
+	}
+}
+
+func TestRingBuffer_SizeClasses(t *testing.T) {
+	rb := New(100, WithSizeClasses())
+	if rb.Capacity() != 128 {
+		t.Fatalf("expect capacity 128 but got %d", rb.Capacity())
+	}
+	if rb.Free() != 128 {
+		t.Fatalf("expect free 128 bytes but got %d", rb.Free())
+	}
+
+	var pool BufferPool
+	pool.Put(New(RoundSize(100)))
+	if rb := pool.Get(RoundSize(120)); rb.Capacity() != 128 {
+		t.Fatalf("expect capacity 128 but got %d", rb.Capacity())
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"math/bits"
+	"time"
+)
+
+// Stats holds statistics about a buffer, collected if the buffer was
+// created with WithStats.
+type Stats struct {
+	// TimeFull is the total time the buffer has spent full while writers
+	// were blocked waiting for space.
+	// A buffer that is often full has a consumer that can't keep up.
+	TimeFull time.Duration
+
+	// TimeEmpty is the total time the buffer has spent empty while readers
+	// were blocked waiting for data.
+	// A buffer that is often empty has a producer that can't keep up.
+	TimeEmpty time.Duration
+}
+
+// stats is the state behind Stats.
+type stats struct {
+	Stats
+	fullSince  time.Time // zero unless the buffer is full with writers waiting
+	emptySince time.Time // zero unless the buffer is empty with readers waiting
+	readers    int       // goroutines waiting to read
+	writers    int       // goroutines waiting to write
+}
+
+// WithStats enables collecting statistics, returned by Stats.
+// Statistics are disabled by default, since timing state changes adds a
+// small cost to reads and writes.
+// Only time that callers spend blocked is measured: a buffer that is full
+// or empty while nobody waits on it is not holding anyone up.
+func WithStats() Option {
+	return func(r *RingBuffer) {
+		r.stats = new(stats)
+	}
+}
+
+// Stats returns the statistics collected since the buffer was created or
+// ResetStats was last called. It returns zero Stats unless the buffer was
+// created with WithStats.
+func (r *RingBuffer) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stats == nil {
+		return Stats{}
+	}
+	s := r.stats.Stats
+	now := time.Now()
+	if !r.stats.fullSince.IsZero() {
+		s.TimeFull += now.Sub(r.stats.fullSince)
+	}
+	if !r.stats.emptySince.IsZero() {
+		s.TimeEmpty += now.Sub(r.stats.emptySince)
+	}
+	return s
+}
+
+// WithWriteSizeHistogram enables counting the sizes of writes made with Write
+// and WriteString, returned by WriteSizeHistogram. It is disabled by default.
+func WithWriteSizeHistogram() Option {
+	return func(r *RingBuffer) {
+		r.writeSizes = make([]uint64, bits.UintSize)
+	}
+}
+
+// WriteSizeHistogram returns the number of writes made with Write and
+// WriteString since the buffer was created or ResetStats was last called,
+// bucketed by size: element i counts writes of at least 1<<i bytes and less
+// than 1<<(i+1) bytes. Empty writes are not counted.
+// It returns nil unless the buffer was created with WithWriteSizeHistogram.
+func (r *RingBuffer) WriteSizeHistogram() []uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writeSizes == nil {
+		return nil
+	}
+	h := make([]uint64, len(r.writeSizes))
+	copy(h, r.writeSizes)
+	return h
+}
+
+// recordWriteSize adds a write of n bytes to the write size histogram,
+// if it is enabled. It must be called with r.mu held.
+func (r *RingBuffer) recordWriteSize(n int) {
+	if r.writeSizes != nil && n > 0 {
+		r.writeSizes[bits.Len(uint(n))-1]++
+	}
+}
+
+// ResetStats resets the statistics, and the write size histogram, to zero.
+func (r *RingBuffer) ResetStats() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.writeSizes {
+		r.writeSizes[i] = 0
+	}
+	if r.stats == nil {
+		return
+	}
+	r.stats.Stats = Stats{}
+	now := time.Now()
+	if !r.stats.fullSince.IsZero() {
+		r.stats.fullSince = now
+	}
+	if !r.stats.emptySince.IsZero() {
+		r.stats.emptySince = now
+	}
+}
+
+// waiting records a goroutine starting (delta 1) or ending (delta -1) a wait
+// on the buffer, so that updateStats times only while callers are blocked.
+// Waits on the write deadline are writers, and all others are readers.
+// It must be called with r.mu held.
+func (r *RingBuffer) waiting(deadline *time.Time, delta int) {
+	s := r.stats
+	if s == nil {
+		return
+	}
+	if deadline == &r.writeDeadline {
+		s.writers += delta
+	} else {
+		s.readers += delta
+	}
+	r.updateStats()
+}
+
+// updateStats times transitions into and out of the full and empty states,
+// counting only while writers or readers, respectively, are waiting.
+// It must be called with r.mu held.
+func (r *RingBuffer) updateStats() {
+	s := r.stats
+	if s == nil {
+		return
+	}
+	now := time.Now()
+	full := r.isFull && s.writers > 0
+	empty := !r.isFull && r.w == r.r && s.readers > 0
+
+	if full && s.fullSince.IsZero() {
+		s.fullSince = now
+	} else if !full && !s.fullSince.IsZero() {
+		s.TimeFull += now.Sub(s.fullSince)
+		s.fullSince = time.Time{}
+	}
+	if empty && s.emptySince.IsZero() {
+		s.emptySince = now
+	} else if !empty && !s.emptySince.IsZero() {
+		s.TimeEmpty += now.Sub(s.emptySince)
+		s.emptySince = time.Time{}
+	}
+}
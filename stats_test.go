@@ -0,0 +1,127 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer_Stats(t *testing.T) {
+	const d = 20 * time.Millisecond
+	rb := New(4, WithStats())
+
+	// nothing is timed while nobody waits
+	time.Sleep(d)
+	if s := rb.Stats(); s.TimeEmpty != 0 {
+		t.Fatalf("expect no TimeEmpty without readers but got %v", s.TimeEmpty)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rb.BlockingRead(make([]byte, 1))
+		done <- err
+	}()
+	waitForWaiters(rb, &rb.stats.readers)
+	time.Sleep(d)
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("BlockingRead failed: %v", err)
+	}
+	if s := rb.Stats(); s.TimeEmpty < d {
+		t.Fatalf("expect TimeEmpty of at least %v but got %v", d, s.TimeEmpty)
+	}
+
+	if _, err := rb.Write([]byte("cde")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	time.Sleep(d)
+	if s := rb.Stats(); s.TimeFull != 0 {
+		t.Fatalf("expect no TimeFull without writers but got %v", s.TimeFull)
+	}
+
+	go func() {
+		_, err := rb.BlockingWrite([]byte("f"))
+		done <- err
+	}()
+	waitForWaiters(rb, &rb.stats.writers)
+	time.Sleep(d)
+	if _, err := rb.ReadByte(); err != nil {
+		t.Fatalf("ReadByte failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("BlockingWrite failed: %v", err)
+	}
+	if s := rb.Stats(); s.TimeFull < d {
+		t.Fatalf("expect TimeFull of at least %v but got %v", d, s.TimeFull)
+	}
+
+	// durations stop growing once nobody waits
+	full := rb.Stats().TimeFull
+	time.Sleep(d)
+	if rb.Stats().TimeFull != full {
+		t.Fatalf("expect TimeFull to stay %v but got %v", full, rb.Stats().TimeFull)
+	}
+
+	rb.ResetStats()
+	if s := rb.Stats(); s.TimeFull != 0 || s.TimeEmpty != 0 {
+		t.Fatalf("expect zero Stats but got %+v", s)
+	}
+
+	if s := New(4).Stats(); s != (Stats{}) {
+		t.Fatalf("expect zero Stats but got %+v", s)
+	}
+}
+
+// waitForWaiters waits until the waiter count n, guarded by rb.mu, is
+// non-zero.
+func waitForWaiters(rb *RingBuffer, n *int) {
+	for {
+		rb.mu.Lock()
+		waiting := *n > 0
+		rb.mu.Unlock()
+		if waiting {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestRingBuffer_WriteSizeHistogram(t *testing.T) {
+	if h := New(8).WriteSizeHistogram(); h != nil {
+		t.Fatalf("expect nil but got %v", h)
+	}
+
+	rb := New(64, WithWriteSizeHistogram())
+	for _, n := range []int{1, 2, 3, 4, 7, 8, 40} {
+		_, _ = rb.Write(make([]byte, n))
+		rb.Reset()
+	}
+	_, _ = rb.WriteString("abcde")
+	_, _ = rb.Write(nil)
+
+	h := rb.WriteSizeHistogram()
+	want := []uint64{1, 2, 3, 1, 0, 1}
+	for i, c := range want {
+		if h[i] != c {
+			t.Fatalf("expect %v but got %v", want, h[:len(want)])
+		}
+	}
+	for _, c := range h[len(want):] {
+		if c != 0 {
+			t.Fatalf("expect no larger writes but got %v", h)
+		}
+	}
+
+	rb.ResetStats()
+	for _, c := range rb.WriteSizeHistogram() {
+		if c != 0 {
+			t.Fatalf("expect an empty histogram but got %v", rb.WriteSizeHistogram())
+		}
+	}
+}
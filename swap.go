@@ -0,0 +1,37 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// Swap installs buf as the underlying buffer, with no unread data,
+// and returns the previous underlying buffer with its n unread bytes moved
+// to the start, as by Compact, so that they are old[:n].
+// This hands over the buffered data without copying it, for example to
+// alternate between two buffers.
+// The buffer takes ownership of buf, and the caller takes ownership of old:
+// the caller must not use buf after the call, and the buffer no longer
+// refers to old. The capacity of the buffer becomes len(buf).
+// Segments returned by DrainSegments and not yet released are invalidated,
+// and releasing them has no effect.
+func (r *RingBuffer) Swap(buf []byte) (old []byte, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.compact()
+	old, n = r.buf, r.length()
+	// The bytes handed off count as skipped, as Reset discards them.
+	r.pos += int64(n)
+
+	r.buf = buf
+	r.size = len(buf)
+	r.r = 0
+	r.w = 0
+	r.replay = 0
+	r.pinned = 0
+	r.isFull = false
+	r.msgs = nil
+	r.changed()
+	r.emptyDue = false
+	return old, n
+}
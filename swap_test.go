@@ -0,0 +1,63 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_Swap(t *testing.T) {
+	rb := New(8)
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	next := make([]byte, 4)
+	old, n := rb.Swap(next)
+	if !bytes.Equal(old[:n], []byte("efghij")) {
+		t.Fatalf("expect efghij but got %s", old[:n])
+	}
+	if len(old) != 8 {
+		t.Fatalf("expect the old buffer of 8 bytes but got %d", len(old))
+	}
+	if !rb.IsEmpty() || rb.Capacity() != 4 {
+		t.Fatalf("expect an empty buffer of capacity 4 but got len %d, cap %d", rb.Length(), rb.Capacity())
+	}
+	// the bytes handed off count as skipped
+	if pos, err := rb.Seek(0, io.SeekCurrent); err != nil || pos != 10 {
+		t.Fatalf("expect offset 10 but got %d, %v", pos, err)
+	}
+
+	if _, err := rb.Write([]byte("klmn")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if &next[0] != &rb.buf[0] {
+		t.Fatalf("expect the new buffer to be used")
+	}
+	if !rb.IsFull() {
+		t.Fatalf("expect IsFull is true but got false")
+	}
+
+	old, n = rb.Swap(old)
+	if !bytes.Equal(old[:n], []byte("klmn")) {
+		t.Fatalf("expect klmn but got %s", old[:n])
+	}
+	if rb.Capacity() != 8 || rb.Cap() != 8 {
+		t.Fatalf("expect capacity 8 but got %d and Cap %d", rb.Capacity(), rb.Cap())
+	}
+	if pos, err := rb.Seek(0, io.SeekCurrent); err != nil || pos != 14 {
+		t.Fatalf("expect offset 14 but got %d, %v", pos, err)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import "hash"
+
+// TeeTo arranges for every byte consumed by Read, ReadByte and the other
+// reading methods to also be written to h, so that h holds a digest of
+// exactly the bytes delivered to the consumer, including record length
+// prefixes. Bytes skipped with Seek are not written to h.
+// Passing nil stops writing to the previous hash.
+func (r *RingBuffer) TeeTo(h hash.Hash) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tee = h
+}
+
+// consumed writes bytes delivered to a reader to the tee, if set.
+// It must be called with r.mu held.
+func (r *RingBuffer) consumed(p []byte) {
+	if r.tee != nil {
+		// hash.Hash never returns an error.
+		_, _ = r.tee.Write(p)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"testing"
+)
+
+func TestRingBuffer_TeeTo(t *testing.T) {
+	rb := New(8)
+	h := sha256.New()
+	rb.TeeTo(h)
+
+	// consume across the wrap with Read and ReadByte, skipping with Seek
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := rb.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Seek(1, io.SeekCurrent); err != nil {
+		t.Fatalf("seek failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghijk")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	b, err := rb.ReadByte()
+	if err != nil {
+		t.Fatalf("ReadByte failed: %v", err)
+	}
+	if b != 'f' {
+		t.Fatalf("expect f but got %c", b)
+	}
+	buf = make([]byte, 8)
+	n, err := rb.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	want := sha256.Sum256([]byte("abcdfghijk"))
+	if !bytes.Equal(h.Sum(nil), want[:]) {
+		t.Fatalf("expect digest of abcdfghijk but got %x after reading %q", h.Sum(nil), buf[:n])
+	}
+
+	// detach the hash
+	rb.TeeTo(nil)
+	if _, err := rb.Write([]byte("z")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.ReadByte(); err != nil {
+		t.Fatalf("ReadByte failed: %v", err)
+	}
+	if !bytes.Equal(h.Sum(nil), want[:]) {
+		t.Fatalf("expect digest of abcdfghijk but got %x", h.Sum(nil))
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// WriteVectored writes the contents of bufs to the buffer, in order,
+// as a single atomic operation.
+// Either all of bufs is written, or nothing is written and ErrFull returned.
+func (r *RingBuffer) WriteVectored(bufs ...[]byte) (n int, err error) {
+	var total int
+	for _, p := range bufs {
+		total += len(p)
+	}
+	if total == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	err = r.writeVectored(bufs, total)
+	hook := r.fullHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// writeVectored writes all of bufs, which hold total bytes, or nothing.
+// It must be called with r.mu held.
+func (r *RingBuffer) writeVectored(bufs [][]byte, total int) error {
+	if err := r.fits(total); err != nil {
+		return err
+	}
+	for _, p := range bufs {
+		if len(p) == 0 {
+			continue
+		}
+		if _, err := r.write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadVectored reads unread bytes into bufs, in order, filling each slice
+// before moving on to the next, until bufs are full or the buffer is empty.
+// It returns the total number of bytes read, and ErrEmpty only if no data
+// was available.
+func (r *RingBuffer) ReadVectored(bufs ...[]byte) (n int, err error) {
+	r.mu.Lock()
+	for _, p := range bufs {
+		if len(p) == 0 {
+			continue
+		}
+		m, rerr := r.read(p)
+		n += m
+		if rerr != nil {
+			if n == 0 {
+				err = rerr
+			}
+			break
+		}
+		if m < len(p) {
+			break
+		}
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRingBuffer_WriteVectored(t *testing.T) {
+	rb := New(8)
+
+	n, err := rb.WriteVectored([]byte("ab"), nil, []byte("cde"))
+	if err != nil {
+		t.Fatalf("WriteVectored failed: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("expect write 5 bytes but got %d", n)
+	}
+
+	// doesn't fit, nothing is written
+	n, err = rb.WriteVectored([]byte("fg"), []byte("hi"))
+	if !errors.Is(err, ErrFull) {
+		t.Fatalf("expect ErrFull but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect write 0 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("abcde")) {
+		t.Fatalf("expect abcde but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// write across the wrap
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	n, err = rb.WriteVectored([]byte("fg"), []byte("hi"), []byte("jkl"))
+	if err != nil {
+		t.Fatalf("WriteVectored failed: %v", err)
+	}
+	if n != 7 {
+		t.Fatalf("expect write 7 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("efghijkl")) {
+		t.Fatalf("expect efghijkl but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_ReadVectored(t *testing.T) {
+	rb := New(8)
+
+	if _, err := rb.ReadVectored(make([]byte, 2)); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+
+	// read across the wrap
+	if _, err := rb.Write([]byte("xxxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("abcdefg")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	hdr, body := make([]byte, 3), make([]byte, 2)
+	n, err := rb.ReadVectored(hdr, nil, body)
+	if err != nil {
+		t.Fatalf("ReadVectored failed: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("expect read 5 bytes but got %d", n)
+	}
+	if string(hdr) != "abc" || string(body) != "de" {
+		t.Fatalf("expect abc and de but got %s and %s", hdr, body)
+	}
+
+	// the buffer drains part way through
+	hdr, body = make([]byte, 1), make([]byte, 4)
+	n, err = rb.ReadVectored(hdr, body)
+	if err != nil {
+		t.Fatalf("ReadVectored failed: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expect read 2 bytes but got %d", n)
+	}
+	if string(hdr) != "f" || !bytes.Equal(body[:1], []byte("g")) {
+		t.Fatalf("expect f and g but got %s and %s", hdr, body[:1])
+	}
+}
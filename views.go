@@ -0,0 +1,170 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"errors"
+	"io"
+)
+
+// Reader returns an io.Reader that consumes bytes from the buffer.
+// Unlike Read, its Read method returns io.EOF instead of ErrEmpty when the
+// buffer has no data, for use with consumers such as bufio.Scanner.
+// Once the buffer is closed and drained, it returns an error that matches
+// both io.EOF and ErrClosed with errors.Is, so callers can tell a closed
+// buffer from one that is only empty for now.
+func (r *RingBuffer) Reader() io.Reader {
+	return reader{r}
+}
+
+type reader struct {
+	r *RingBuffer
+}
+
+func (r reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	switch {
+	case errors.Is(err, ErrEmpty):
+		err = io.EOF
+	case errors.Is(err, ErrClosed):
+		err = errClosedEOF
+	}
+	return n, err
+}
+
+// errClosedEOF is returned by Reader once the buffer is closed and drained.
+// It is like errors.Join(io.EOF, ErrClosed), which needs Go 1.20.
+var errClosedEOF error = closedEOFError{}
+
+type closedEOFError struct{}
+
+func (closedEOFError) Error() string { return ErrClosed.Error() + ": " + io.EOF.Error() }
+
+func (closedEOFError) Is(target error) bool { return target == io.EOF || target == ErrClosed }
+
+// Writer returns an io.Writer that writes to the buffer.
+// Unlike Write, its Write method blocks until all of p has been written
+// instead of returning ErrFull or ErrShortWrite, for use with producers such as io.Copy.
+// It returns ErrClosed if the buffer is closed, and ErrTimeout if the write
+// deadline passes.
+func (r *RingBuffer) Writer() io.Writer {
+	return writer{r}
+}
+
+type writer struct {
+	r *RingBuffer
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	return w.r.BlockingWrite(p)
+}
+
+// ReadWriteCloser returns an io.ReadWriteCloser whose Read and Write
+// methods block like BlockingRead and BlockingWrite, for code that expects
+// one end of a pipe. Once the buffer is closed, its Read method returns
+// io.EOF when the buffer has been drained, and its Write method returns
+// ErrClosed. Its Close method closes the buffer.
+func (r *RingBuffer) ReadWriteCloser() io.ReadWriteCloser {
+	return readWriteCloser{r}
+}
+
+type readWriteCloser struct {
+	r *RingBuffer
+}
+
+func (rw readWriteCloser) Read(p []byte) (int, error) {
+	n, err := rw.r.BlockingRead(p)
+	if errors.Is(err, ErrClosed) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (rw readWriteCloser) Write(p []byte) (int, error) {
+	return rw.r.BlockingWrite(p)
+}
+
+func (rw readWriteCloser) Close() error {
+	return rw.r.Close()
+}
+
+// LimitReader returns an io.Reader that consumes at most n bytes from the
+// buffer, and then returns io.EOF. It never reads past the limit, even if
+// more data is buffered. Before the limit is reached, its Read method
+// returns the same errors as Read, including ErrEmpty.
+func (r *RingBuffer) LimitReader(n int) io.Reader {
+	return &limitReader{r, n}
+}
+
+type limitReader struct {
+	r *RingBuffer
+	n int // bytes remaining
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return 0, io.EOF
+	}
+	if len(p) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= n
+	return n, err
+}
+
+// TeeReader returns an io.Reader that consumes bytes from the buffer and
+// also writes them to w, like io.TeeReader. Bytes are written to w directly
+// from the underlying buffer, and only bytes accepted by w are consumed.
+// An error from w is returned from Read, along with the number of bytes
+// consumed before the error. If w accepts fewer bytes than it was given
+// without an error, Read returns io.ErrShortWrite, as Flush does.
+// The buffer is locked while writing to w, so w must not call methods on
+// the buffer.
+func (r *RingBuffer) TeeReader(w io.Writer) io.Reader {
+	return teeReader{r, w}
+}
+
+type teeReader struct {
+	r *RingBuffer
+	w io.Writer
+}
+
+func (t teeReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	r := t.r
+	r.mu.Lock()
+	if !r.readable() {
+		err = r.emptyErr()
+	}
+	first, second := r.segments()
+	for _, seg := range [2][]byte{first, second} {
+		if err != nil || n == len(p) {
+			break
+		}
+		if len(seg) > len(p)-n {
+			seg = seg[:len(p)-n]
+		}
+		var m int
+		m, err = t.w.Write(seg)
+		copy(p[n:], seg[:m])
+		n += m
+		if err == nil && m < len(seg) {
+			err = io.ErrShortWrite
+		}
+	}
+	r.consumed(p[:n])
+	r.advance(n)
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return n, err
+}
@@ -0,0 +1,320 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer_Reader(t *testing.T) {
+	rb := New(64)
+	if _, err := rb.WriteString("one\ntwo\nthree"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	var lines []string
+	s := bufio.NewScanner(rb.Reader())
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(lines) != 3 || lines[0] != "one" || lines[1] != "two" || lines[2] != "three" {
+		t.Fatalf("expect lines one, two, three but got %q", lines)
+	}
+
+	n, err := rb.Reader().Read(make([]byte, 1))
+	if err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect read 0 bytes but got %d", n)
+	}
+}
+
+func TestRingBuffer_ReaderClosed(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.WriteString("ab"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = rb.Close()
+
+	buf := make([]byte, 4)
+	n, err := rb.Reader().Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !bytes.Equal(buf[:n], []byte("ab")) {
+		t.Fatalf("expect ab but got %s", buf[:n])
+	}
+
+	_, err = rb.Reader().Read(buf)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+	if !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+	if errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect not ErrEmpty but got %v", err)
+	}
+}
+
+func TestRingBuffer_ReadWriteCloser(t *testing.T) {
+	rb := New(4)
+	rwc := rb.ReadWriteCloser()
+	data := []byte(strings.Repeat("abcd", 16))
+
+	go func() {
+		if _, err := rwc.Write(data); err != nil {
+			t.Errorf("write failed: %v", err)
+		}
+		_ = rwc.Close()
+	}()
+	got, err := io.ReadAll(rwc)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("expect 16 abcd but got %s", got)
+	}
+
+	if _, err := rwc.Write([]byte("a")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_Writer(t *testing.T) {
+	rb := New(4)
+	data := []byte(strings.Repeat("abcd", 64))
+
+	done := make(chan []byte)
+	go func() {
+		var got []byte
+		buf := make([]byte, 3)
+		for {
+			n, err := rb.Read(buf)
+			got = append(got, buf[:n]...)
+			if errors.Is(err, ErrClosed) {
+				break
+			}
+			if errors.Is(err, ErrEmpty) {
+				runtime.Gosched()
+			}
+		}
+		done <- got
+	}()
+
+	n, err := io.Copy(rb.Writer(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("expect copy %d bytes but got %d", len(data), n)
+	}
+	if err := rb.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if got := <-done; !bytes.Equal(got, data) {
+		t.Fatalf("expect %d bytes of abcd but got %q", len(data), got)
+	}
+
+	n2, err := rb.Writer().Write([]byte("a"))
+	if !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+	if n2 != 0 {
+		t.Fatalf("expect write 0 bytes but got %d", n2)
+	}
+}
+
+func TestRingBuffer_WriterClose(t *testing.T) {
+	rb := New(2)
+
+	done := make(chan error)
+	go func() {
+		_, err := rb.Writer().Write([]byte("abcd"))
+		done <- err
+	}()
+
+	// wait for the writer to fill the buffer and block
+	for !rb.IsFull() {
+		runtime.Gosched()
+	}
+	if err := rb.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Fatalf("expect ErrClosed but got %v", err)
+	}
+}
+
+func TestRingBuffer_LimitReader(t *testing.T) {
+	rb := New(16)
+	if _, err := rb.WriteString("abcdefgh"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	lr := rb.LimitReader(5)
+	buf := make([]byte, 3)
+	n, err := lr.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if n != 3 || string(buf[:n]) != "abc" {
+		t.Fatalf("expect abc but got %q", buf[:n])
+	}
+	n, err = lr.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if n != 2 || string(buf[:n]) != "de" {
+		t.Fatalf("expect de but got %q", buf[:n])
+	}
+	n, err = lr.Read(buf)
+	if err != io.EOF {
+		t.Fatalf("expect io.EOF but got %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expect read 0 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("fgh")) {
+		t.Fatalf("expect fgh but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	// the buffer drains before the limit is reached
+	lr = rb.LimitReader(5)
+	got, err := io.ReadAll(io.LimitReader(lr, 3))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(got) != "fgh" {
+		t.Fatalf("expect fgh but got %q", got)
+	}
+	if _, err := lr.Read(buf); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+}
+
+func TestRingBuffer_TeeReader(t *testing.T) {
+	rb := New(8)
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("xxxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	var log bytes.Buffer
+	tr := rb.TeeReader(&log)
+	buf := make([]byte, 5)
+	n, err := tr.Read(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(buf[:n]) != "abcde" {
+		t.Fatalf("expect abcde but got %q", buf[:n])
+	}
+	if log.String() != "abcde" {
+		t.Fatalf("expect abcde to be logged but got %q", log.String())
+	}
+	if _, err := tr.Read(buf); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := tr.Read(buf); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if log.String() != "abcdef" {
+		t.Fatalf("expect abcdef to be logged but got %q", log.String())
+	}
+}
+
+func TestRingBuffer_TeeReaderError(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// the writer only accepts 2 bytes
+	w := &limitedWriter{n: 2}
+	buf := make([]byte, 8)
+	n, err := rb.TeeReader(w).Read(buf)
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("expect io.ErrShortWrite but got %v", err)
+	}
+	if n != 2 || string(buf[:n]) != "ab" {
+		t.Fatalf("expect ab but got %q", buf[:n])
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cdef")) {
+		t.Fatalf("expect cdef but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_TeeReaderShortWrite(t *testing.T) {
+	rb := New(8)
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// the writer accepts 1 byte of each write without an error
+	w := &shortWriter{}
+	buf := make([]byte, 8)
+	n, err := rb.TeeReader(w).Read(buf)
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("expect io.ErrShortWrite but got %v", err)
+	}
+	if n != 1 || string(buf[:n]) != "e" || w.String() != "e" {
+		t.Fatalf("expect e but got %q, logged %q", buf[:n], w.String())
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("fghij")) {
+		t.Fatalf("expect fghij but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+}
+
+// shortWriter accepts only the first byte of each write, without an error.
+type shortWriter struct {
+	bytes.Buffer
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > 1 {
+		p = p[:1]
+	}
+	return w.Buffer.Write(p)
+}
+
+// limitedWriter accepts up to n bytes, then returns io.ErrShortWrite.
+type limitedWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n, _ := w.Buffer.Write(p[:w.n])
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(p)
+	return w.Buffer.Write(p)
+}
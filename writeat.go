@@ -0,0 +1,30 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// WriteAt implements io.WriterAt over the unread data, overwriting the bytes
+// at offset off from the oldest unread byte without moving the read or write
+// positions. It is meant for patching data that is already buffered, such as
+// filling in a length prefix once the payload that follows it is written.
+// It writes nothing and returns ErrOutOfRange unless all of p falls within
+// the unread data.
+// The checksum kept by WithChecksum covers bytes as originally written,
+// and is not updated.
+func (r *RingBuffer) WriteAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off < 0 || off > int64(r.length())-int64(len(p)) {
+		return 0, ErrOutOfRange
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	start := (r.r + int(off)) % r.size
+	n := copy(r.buf[start:], p)
+	copy(r.buf, p[n:])
+	return len(p), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+var _ io.WriterAt = (*RingBuffer)(nil)
+
+func TestRingBuffer_WriteAt(t *testing.T) {
+	rb := New(8)
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// patch across the wrap
+	n, err := rb.WriteAt([]byte("XYZ"), 1)
+	if err != nil {
+		t.Fatalf("WriteAt failed: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expect write 3 bytes but got %d", n)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("eXYZij")) {
+		t.Fatalf("expect eXYZij but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	if _, err := rb.WriteAt([]byte("kl"), 5); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	if _, err := rb.WriteAt([]byte("k"), -1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	// off+len(p) would overflow
+	if _, err := rb.WriteAt([]byte("k"), math.MaxInt64); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expect ErrOutOfRange but got %v", err)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("eXYZij")) {
+		t.Fatalf("expect eXYZij but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_WriteAtLengthPrefix(t *testing.T) {
+	rb := New(16)
+
+	// reserve the prefix, write the payload, then fill in its length
+	if err := rb.WriteUint16BE(0); err != nil {
+		t.Fatalf("WriteUint16BE failed: %v", err)
+	}
+	if _, err := rb.WriteString("hello"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.WriteAt([]byte{0, 5}, 0); err != nil {
+		t.Fatalf("WriteAt failed: %v", err)
+	}
+	if v, err := rb.ReadUint16BE(); err != nil || v != 5 {
+		t.Fatalf("expect 5 but got %d, %v", v, err)
+	}
+}
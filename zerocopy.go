@@ -0,0 +1,148 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+// Next returns a slice containing up to the next n unread bytes and advances
+// the buffer as if the bytes had been returned by Read.
+// Since the slice aliases the underlying buffer, it only covers unread bytes
+// up to the end of the underlying buffer, and so may be shorter than n even
+// if more data is buffered. It returns nil if the buffer is empty.
+// The slice is only valid until the next write to the buffer.
+func (r *RingBuffer) Next(n int) []byte {
+	r.mu.Lock()
+
+	if m := r.length(); n > m {
+		n = m
+	}
+	if c := r.size - r.r; n > c {
+		n = c
+	}
+	if n <= 0 {
+		r.mu.Unlock()
+		return nil
+	}
+	p := r.buf[r.r : r.r+n : r.r+n]
+	r.consumed(p)
+	r.advance(n)
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return p
+}
+
+// ForEachSegment calls fn with each contiguous segment of the unread data,
+// in order, which is one segment, or two if the data wraps around the end of
+// the underlying buffer. Each segment is consumed once fn returns nil for it.
+// If fn returns an error, ForEachSegment stops and returns the error without
+// consuming that segment.
+// The segments alias the underlying buffer and must not be retained after fn
+// returns. The buffer is locked while fn runs, so fn must not call methods
+// on the buffer.
+func (r *RingBuffer) ForEachSegment(fn func([]byte) error) error {
+	r.mu.Lock()
+	var err error
+	first, second := r.segments()
+	for _, seg := range [2][]byte{first, second} {
+		if len(seg) == 0 {
+			continue
+		}
+		if err = fn(seg); err != nil {
+			break
+		}
+		r.consumed(seg)
+		r.advance(len(seg))
+	}
+	hook := r.emptyHook()
+	r.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return err
+}
+
+// PeekContiguous returns a slice of the next n unread bytes without
+// consuming them, if they are stored contiguously in the underlying buffer.
+// It returns false if fewer than n bytes are buffered, or if they wrap
+// around the end of the underlying buffer, in which case the caller can
+// fall back to Peek.
+// The slice aliases the underlying buffer. It must not be modified, and is
+// only valid until the bytes are consumed, after which writes may
+// overwrite them.
+func (r *RingBuffer) PeekContiguous(n int) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n < 0 || n > r.length() || r.r+n > r.size {
+		return nil, false
+	}
+	return r.buf[r.r : r.r+n : r.r+n], true
+}
+
+// Peek copies up to len(p) unread bytes into p without consuming them.
+// It returns ErrEmpty if the buffer is empty, or ErrClosed if it is
+// also closed.
+func (r *RingBuffer) Peek(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.length() == 0 {
+		return 0, r.emptyErr()
+	}
+	return r.peek(p), nil
+}
+
+// PeekSegments returns up to the next n unread bytes without consuming them,
+// as one or two slices of the underlying buffer. The second slice is nil
+// unless the bytes wrap around the end of the underlying buffer. Both are
+// nil if the buffer is empty or n is not positive.
+// The slices alias the underlying buffer. They must not be modified, and are
+// only valid until the bytes are consumed, after which writes may
+// overwrite them.
+func (r *RingBuffer) PeekSegments(n int) (first, second []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first, second = r.segments()
+	if n <= len(first) {
+		if n <= 0 {
+			return nil, nil
+		}
+		return first[:n:n], nil
+	}
+	if n -= len(first); n < len(second) {
+		second = second[:n]
+	}
+	return first[:len(first):len(first)], second[:len(second):len(second)]
+}
+
+// BytesView returns all unread bytes without copying them, if they are
+// stored contiguously in the underlying buffer. It returns nil and false
+// if they wrap around the end of the underlying buffer, in which case the
+// caller can fall back to Bytes. An empty buffer returns an empty slice
+// and true.
+// Unlike Bytes, the slice aliases the underlying buffer. It must not be
+// modified, and is only valid until the bytes are consumed, after which
+// writes may overwrite them.
+func (r *RingBuffer) BytesView() ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first, second := r.segments()
+	if second != nil {
+		return nil, false
+	}
+	if first == nil {
+		return []byte{}, true
+	}
+	return first[:len(first):len(first)], true
+}
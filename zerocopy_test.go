@@ -0,0 +1,224 @@
+// Copyright 2019 smallnest, 2023 Ananth Bhaskararaman. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRingBuffer_Next(t *testing.T) {
+	rb := New(8)
+
+	if p := rb.Next(4); p != nil {
+		t.Fatalf("expect nil but got %q", p)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if p := rb.Next(4); !bytes.Equal(p, []byte("abcd")) {
+		t.Fatalf("expect abcd but got %q", p)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// stops at the end of the underlying buffer
+	if p := rb.Next(10); !bytes.Equal(p, []byte("efgh")) {
+		t.Fatalf("expect efgh but got %q", p)
+	}
+	if p := rb.Next(10); !bytes.Equal(p, []byte("ij")) {
+		t.Fatalf("expect ij but got %q", p)
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false")
+	}
+}
+
+func TestRingBuffer_ForEachSegment(t *testing.T) {
+	rb := New(8)
+
+	// wrap the unread data around the end of the buffer
+	if _, err := rb.Write([]byte("xxxxxx")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 6)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("abcde")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// stop at the second segment
+	errStop := errors.New("stop")
+	var got []string
+	err := rb.ForEachSegment(func(p []byte) error {
+		if len(got) == 1 {
+			return errStop
+		}
+		got = append(got, string(p))
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expect errStop but got %v", err)
+	}
+	if len(got) != 1 || got[0] != "ab" {
+		t.Fatalf("expect segment ab but got %q", got)
+	}
+	if !bytes.Equal(rb.Bytes(), []byte("cde")) {
+		t.Fatalf("expect cde but got %s. r.w=%d, r.r=%d", rb.Bytes(), rb.w, rb.r)
+	}
+
+	var w bytes.Buffer
+	err = rb.ForEachSegment(func(p []byte) error {
+		_, err := w.Write(p)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("ForEachSegment failed: %v", err)
+	}
+	if w.String() != "cde" {
+		t.Fatalf("expect cde but got %q", w.String())
+	}
+	if !rb.IsEmpty() {
+		t.Fatalf("expect IsEmpty is true but got false")
+	}
+}
+
+func TestRingBuffer_PeekContiguous(t *testing.T) {
+	rb := New(8)
+
+	if _, ok := rb.PeekContiguous(1); ok {
+		t.Fatalf("expect PeekContiguous to fail on an empty buffer")
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	p, ok := rb.PeekContiguous(4)
+	if !ok {
+		t.Fatalf("expect PeekContiguous to succeed. r.w=%d, r.r=%d", rb.w, rb.r)
+	}
+	if !bytes.Equal(p, []byte("efgh")) {
+		t.Fatalf("expect efgh but got %s", p)
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+
+	// the next 5 bytes wrap around
+	if _, ok := rb.PeekContiguous(5); ok {
+		t.Fatalf("expect PeekContiguous to fail across the wrap")
+	}
+	buf := make([]byte, 5)
+	n, err := rb.Peek(buf)
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if !bytes.Equal(buf[:n], []byte("efghi")) {
+		t.Fatalf("expect efghi but got %s", buf[:n])
+	}
+
+	if _, ok := rb.PeekContiguous(7); ok {
+		t.Fatalf("expect PeekContiguous to fail past the buffered data")
+	}
+}
+
+func TestRingBuffer_Peek(t *testing.T) {
+	rb := New(4)
+
+	if _, err := rb.Peek(make([]byte, 1)); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("expect ErrEmpty but got %v", err)
+	}
+	if _, err := rb.Write([]byte("ab")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	buf := make([]byte, 4)
+	n, err := rb.Peek(buf)
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if n != 2 || !bytes.Equal(buf[:n], []byte("ab")) {
+		t.Fatalf("expect ab but got %s", buf[:n])
+	}
+	if rb.Length() != 2 {
+		t.Fatalf("expect len 2 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_PeekSegments(t *testing.T) {
+	rb := New(8)
+
+	if first, second := rb.PeekSegments(4); first != nil || second != nil {
+		t.Fatalf("expect nil segments but got %q, %q", first, second)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	first, second := rb.PeekSegments(3)
+	if string(first) != "efg" || second != nil {
+		t.Fatalf("expect efg and nil but got %q, %q. r.w=%d, r.r=%d", first, second, rb.w, rb.r)
+	}
+	first, second = rb.PeekSegments(5)
+	if string(first) != "efgh" || string(second) != "i" {
+		t.Fatalf("expect efgh and i but got %q, %q. r.w=%d, r.r=%d", first, second, rb.w, rb.r)
+	}
+	first, second = rb.PeekSegments(100)
+	if string(first) != "efgh" || string(second) != "ij" {
+		t.Fatalf("expect efgh and ij but got %q, %q. r.w=%d, r.r=%d", first, second, rb.w, rb.r)
+	}
+	if first, second = rb.PeekSegments(0); first != nil || second != nil {
+		t.Fatalf("expect nil segments but got %q, %q", first, second)
+	}
+	if rb.Length() != 6 {
+		t.Fatalf("expect len 6 bytes but got %d. r.w=%d, r.r=%d", rb.Length(), rb.w, rb.r)
+	}
+}
+
+func TestRingBuffer_BytesView(t *testing.T) {
+	rb := New(8)
+
+	p, ok := rb.BytesView()
+	if !ok || p == nil || len(p) != 0 {
+		t.Fatalf("expect an empty view but got %q, %v", p, ok)
+	}
+
+	if _, err := rb.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := rb.Read(make([]byte, 4)); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if p, ok = rb.BytesView(); !ok || !bytes.Equal(p, []byte("ef")) {
+		t.Fatalf("expect ef but got %q, %v. r.w=%d, r.r=%d", p, ok, rb.w, rb.r)
+	}
+	if &p[0] != &rb.buf[4] {
+		t.Fatalf("expect the view to alias the buffer")
+	}
+
+	if _, err := rb.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if p, ok = rb.BytesView(); ok || p != nil {
+		t.Fatalf("expect nil and false but got %q, %v. r.w=%d, r.r=%d", p, ok, rb.w, rb.r)
+	}
+}